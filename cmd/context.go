@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"path/filepath"
+
 	"github.com/noizwaves/grab/pkg"
+	"github.com/noizwaves/grab/pkg/github"
+	"github.com/noizwaves/grab/pkg/gitlab"
+	"github.com/noizwaves/grab/pkg/oci"
 	"github.com/spf13/viper"
 )
 
@@ -11,3 +16,49 @@ func newGrabContext() (*pkg.GrabContext, error) {
 
 	return pkg.NewGrabContext(configPath, binPath) //nolint:wrapcheck
 }
+
+func newSources(gCtx *pkg.GrabContext) *pkg.Sources {
+	return &pkg.Sources{
+		GitHubClient: newGitHubClient(gCtx, github.DefaultHost),
+		NewGitHubEnterpriseClient: func(host string) github.Client {
+			return newGitHubClient(gCtx, host)
+		},
+		NewGitLabClient: func(host string) gitlab.Client {
+			return newGitLabClient(gCtx, host)
+		},
+		NewGiteaClient: func(host string) github.Client {
+			return newGiteaClient(gCtx, host)
+		},
+		NewOCIClient: func(host string) oci.Client {
+			return oci.NewClient(host, gCtx.HTTPClient)
+		},
+		HTTPClient: gCtx.HTTPClient,
+	}
+}
+
+// newGitHubClient creates a client for github.com or a GitHub Enterprise Server host, as configured by settings.
+func newGitHubClient(gCtx *pkg.GrabContext, host string) *github.ClientImpl {
+	return newClient(gCtx, gCtx.Config.Settings.GitHubClientConfig(host), "github")
+}
+
+// newGiteaClient creates a client for a Gitea or Forgejo host, as configured by settings.
+func newGiteaClient(gCtx *pkg.GrabContext, host string) *github.ClientImpl {
+	return newClient(gCtx, gCtx.Config.Settings.GiteaClientConfig(host), "gitea")
+}
+
+// newGitLabClient creates a client for a GitLab host, as configured by settings.
+func newGitLabClient(gCtx *pkg.GrabContext, host string) *gitlab.ClientImpl {
+	config := gCtx.Config.Settings.GitLabClientConfig(host)
+	config.HTTPClient = gCtx.HTTPClient
+
+	return gitlab.NewClientWithConfig(config)
+}
+
+// newClient creates a GitHub compatible client, caching responses in a subdirectory of the cache named cacheName.
+func newClient(gCtx *pkg.GrabContext, config github.ClientConfig, cacheName string) *github.ClientImpl {
+	config.WaitForRateLimit = viper.GetBool("wait-for-rate-limit")
+	config.CacheDir = filepath.Join(gCtx.CacheDir, cacheName)
+	config.HTTPClient = gCtx.HTTPClient
+
+	return github.NewClientWithConfig(config)
+}
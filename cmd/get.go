@@ -1,17 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/noizwaves/grab/pkg"
-	"github.com/noizwaves/grab/pkg/github"
-	"github.com/noizwaves/grab/pkg/importer"
 	"github.com/spf13/cobra"
 )
 
 func makeGetCommand() *cobra.Command {
-	var packageName string
+	var (
+		packageName, sourceKind string
+		platforms               []string
+		bestEffort              bool
+	)
 
 	getCmd := &cobra.Command{
 		Use:   "get [GITHUB_REPO_URL]",
@@ -26,6 +29,9 @@ Arguments:
 
 Flags:
   -n, --name string: Override package name (default: repository name, must be lowercase with no whitespace)
+  --source string: Kind of source hosting the repository, one of github or gitea (default: github)
+  --platforms strings: Import only these platform/arch pairs, all of which must have assets (e.g., linux/amd64,linux/arm64)
+  --best-effort: Import the platforms that have assets, rather than failing when one is missing
 `,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
@@ -33,8 +39,11 @@ Flags:
 			err := configureLogging()
 			cobra.CheckErr(err)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runGetCommand(packageName, args)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := newCommandContext(cmd)
+			defer cancel()
+
+			return runGetCommand(ctx, packageName, sourceKind, platforms, bestEffort, args)
 		},
 	}
 
@@ -43,10 +52,27 @@ Flags:
 		"Override package name (must be lowercase with no whitespace)",
 	)
 
+	getCmd.Flags().StringVar(
+		&sourceKind, "source", pkg.SourceKindGitHub,
+		"Kind of source hosting the repository, one of github or gitea",
+	)
+
+	getCmd.Flags().StringSliceVar(
+		&platforms, "platforms", nil,
+		"Import only these platform/arch pairs, all of which must have assets (e.g., linux/amd64,linux/arm64)",
+	)
+
+	getCmd.Flags().BoolVar(
+		&bestEffort, "best-effort", false,
+		"Import the platforms that have assets, rather than failing when one is missing",
+	)
+
 	return getCmd
 }
 
-func runGetCommand(packageName string, args []string) error {
+func runGetCommand(
+	ctx context.Context, packageName, sourceKind string, platforms []string, bestEffort bool, args []string,
+) error {
 	if packageName != "" {
 		err := validatePackageName(packageName)
 		if err != nil {
@@ -56,19 +82,22 @@ func runGetCommand(packageName string, args []string) error {
 
 	inputURL := args[0]
 
-	err := validateGitHubRepoURL(inputURL)
+	gCtx, err := newGrabContext()
 	if err != nil {
-		return fmt.Errorf("invalid GitHub release URL: %w", err)
+		return fmt.Errorf("error loading context: %w", err)
 	}
 
-	gCtx, err := newGrabContext()
+	imp, err := newImporter(gCtx, sourceKind, inputURL)
 	if err != nil {
-		return fmt.Errorf("error loading context: %w", err)
+		return err
 	}
 
-	imp := importer.NewImporter(github.NewClient())
+	err = configureImporter(imp, platforms, bestEffort)
+	if err != nil {
+		return err
+	}
 
-	result, err := imp.ImportPackage(gCtx, inputURL, packageName, os.Stdout)
+	result, err := imp.ImportPackage(ctx, gCtx, inputURL, packageName, os.Stdout)
 	if err != nil {
 		return fmt.Errorf("error importing: %w", err)
 	}
@@ -85,11 +114,15 @@ func runGetCommand(packageName string, args []string) error {
 		return fmt.Errorf("error reloading context: %w", err)
 	}
 
+	reporter, closeReporter := newReporter()
+	defer closeReporter()
+
 	installer := pkg.Installer{
-		GitHubClient: github.NewClient(),
+		Sources:  newSources(gCtx),
+		Progress: reporter,
 	}
 
-	err = installer.Install(gCtx, result.PackageName, os.Stdout)
+	err = installer.Install(ctx, gCtx, result.PackageName, reporter)
 	if err != nil {
 		return fmt.Errorf("error installing: %w", err)
 	}
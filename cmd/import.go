@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/noizwaves/grab/pkg"
 	"github.com/noizwaves/grab/pkg/github"
 	"github.com/noizwaves/grab/pkg/importer"
 	"github.com/spf13/cobra"
@@ -14,7 +15,11 @@ import (
 
 //nolint:lll
 func makeImportCommand() *cobra.Command {
-	var packageName string
+	var (
+		packageName, sourceKind string
+		platforms               []string
+		bestEffort              bool
+	)
 
 	importCmd := &cobra.Command{
 		Use:   "import [GITHUB_REPO_URL]",
@@ -27,6 +32,9 @@ Arguments:
 
 Flags:
   -n, --name string: Override package name (default: repository name, must be lowercase with no whitespace)
+  --source string: Kind of source hosting the repository, one of github or gitea (default: github)
+  --platforms strings: Import only these platform/arch pairs, all of which must have assets (e.g., linux/amd64,linux/arm64)
+  --best-effort: Import the platforms that have assets, rather than failing when one is missing
 `,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
@@ -34,7 +42,10 @@ Flags:
 			err := configureLogging()
 			cobra.CheckErr(err)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := newCommandContext(cmd)
+			defer cancel()
+
 			// Validate package name if provided
 			if packageName != "" {
 				err := validatePackageName(packageName)
@@ -50,15 +61,17 @@ Flags:
 
 			inputURL := args[0]
 
-			// Validate GitHub release URL
-			err = validateGitHubRepoURL(inputURL)
+			imp, err := newImporter(gCtx, sourceKind, inputURL)
 			if err != nil {
-				return fmt.Errorf("invalid GitHub release URL: %w", err)
+				return err
 			}
 
-			importer := importer.NewImporter(github.NewClient())
+			err = configureImporter(imp, platforms, bestEffort)
+			if err != nil {
+				return err
+			}
 
-			err = importer.Import(gCtx, inputURL, packageName, os.Stdout)
+			err = imp.Import(ctx, gCtx, inputURL, packageName, os.Stdout)
 			if err != nil {
 				return fmt.Errorf("error installing: %w", err)
 			}
@@ -68,14 +81,62 @@ Flags:
 	}
 
 	importCmd.Flags().StringVarP(&packageName, "name", "n", "", "Override package name (must be lowercase with no whitespace)")
+	importCmd.Flags().StringVar(&sourceKind, "source", pkg.SourceKindGitHub, "Kind of source hosting the repository, one of github or gitea")
+	importCmd.Flags().StringSliceVar(&platforms, "platforms", nil, "Import only these platform/arch pairs, all of which must have assets (e.g., linux/amd64,linux/arm64)")
+	importCmd.Flags().BoolVar(&bestEffort, "best-effort", false, "Import the platforms that have assets, rather than failing when one is missing")
 
 	return importCmd
 }
 
-// validateGitHubRepoURL validates that the URL is a valid GitHub URL.
-// Valid URL scheme: https://github.com/<org>/<repo>/*
-func validateGitHubRepoURL(inputURL string) error {
-	parsedURL, err := validateGitHubURL(inputURL)
+// newImporter creates an importer for the kind of source, after validating the URL suits it.
+// GitHub URLs may be from github.com or a GitHub Enterprise Server instance configured in settings.
+func newImporter(gCtx *pkg.GrabContext, sourceKind, inputURL string) (*importer.Importer, error) {
+	switch sourceKind {
+	case pkg.SourceKindGitHub:
+		host := github.DefaultHost
+		if parsedURL, err := url.Parse(inputURL); err == nil && gCtx.Config.Settings.IsGitHubHost(parsedURL.Host) {
+			host = parsedURL.Host
+		}
+
+		err := validateGitHubRepoURL(inputURL, host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub release URL: %w", err)
+		}
+
+		if host != github.DefaultHost {
+			return importer.NewGitHubEnterpriseImporter(newGitHubClient(gCtx, host), host), nil
+		}
+
+		return importer.NewImporter(newGitHubClient(gCtx, host)), nil
+	case pkg.SourceKindGitea:
+		parsedURL, err := url.Parse(inputURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL format: %w", err)
+		}
+
+		return importer.NewGiteaImporter(newGiteaClient(gCtx, parsedURL.Host), parsedURL.Host), nil
+	default:
+		return nil, fmt.Errorf("unsupported source %q, expected github or gitea", sourceKind)
+	}
+}
+
+// configureImporter applies the --platforms and --best-effort flags to imp.
+func configureImporter(imp *importer.Importer, platforms []string, bestEffort bool) error {
+	pairs, err := importer.ParsePlatforms(platforms)
+	if err != nil {
+		return fmt.Errorf("invalid --platforms: %w", err)
+	}
+
+	imp.Platforms = pairs
+	imp.BestEffort = bestEffort
+
+	return nil
+}
+
+// validateGitHubRepoURL validates that the URL is a valid GitHub URL served from host.
+// Valid URL scheme: https://<host>/<org>/<repo>/*
+func validateGitHubRepoURL(inputURL, host string) error {
+	parsedURL, err := validateGitHubURL(inputURL, host)
 	if err != nil {
 		return err
 	}
@@ -94,7 +155,7 @@ func validateGitHubRepoURL(inputURL string) error {
 	return nil
 }
 
-func validateGitHubURL(inputURL string) (*url.URL, error) {
+func validateGitHubURL(inputURL, host string) (*url.URL, error) {
 	parsedURL, err := url.Parse(inputURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL format: %w", err)
@@ -104,8 +165,8 @@ func validateGitHubURL(inputURL string) (*url.URL, error) {
 		return nil, fmt.Errorf("URL must use HTTPS scheme, got: %s", parsedURL.Scheme)
 	}
 
-	if parsedURL.Host != "github.com" {
-		return nil, fmt.Errorf("URL must be from github.com, got: %s", parsedURL.Host)
+	if parsedURL.Host != host {
+		return nil, fmt.Errorf("URL must be from %s, got: %s", host, parsedURL.Host)
 	}
 
 	return parsedURL, nil
@@ -1,47 +1,95 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/noizwaves/grab/pkg"
-	"github.com/noizwaves/grab/pkg/github"
 	"github.com/spf13/cobra"
 )
 
 func makeInstallCommand() *cobra.Command {
+	var save bool
+
 	installCmd := &cobra.Command{
-		Use:          "install [package-name]",
-		Short:        "Install missing dependencies",
+		Use:   "install [package-name[@version]]",
+		Short: "Install missing dependencies",
+		Long: `
+Install missing dependencies. Defaults to installing all packages at their configured versions.
+
+Arguments:
+  package-name (optional): Name of the package to install (e.g., "jq")
+  version (optional): Version to install instead of the configured one (e.g., "jq@1.6")
+
+Flags:
+  --save: Update the configured version to the installed version
+`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		PreRun: func(_ *cobra.Command, _ []string) {
 			err := configureLogging()
 			cobra.CheckErr(err)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			gCtx, err := newGrabContext()
-			if err != nil {
-				return fmt.Errorf("error loading context: %w", err)
-			}
-
-			installer := pkg.Installer{
-				GitHubClient: github.NewClient(),
-			}
-
-			var packageName string
-			if len(args) > 0 {
-				packageName = args[0]
-			}
-
-			err = installer.Install(gCtx, packageName, os.Stdout)
-			if err != nil {
-				return fmt.Errorf("error installing: %w", err)
-			}
-
-			return nil
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := newCommandContext(cmd)
+			defer cancel()
+
+			return runInstallCommand(ctx, args, save)
 		},
 	}
 
+	installCmd.Flags().BoolVar(&save, "save", false, "Update the configured version to the installed version")
+
 	return installCmd
 }
+
+func runInstallCommand(ctx context.Context, args []string, save bool) error {
+	gCtx, err := newGrabContext()
+	if err != nil {
+		return fmt.Errorf("error loading context: %w", err)
+	}
+
+	reporter, closeReporter := newReporter()
+	defer closeReporter()
+
+	installer := pkg.Installer{
+		Sources:  newSources(gCtx),
+		Progress: reporter,
+	}
+
+	var packageName, version string
+	if len(args) > 0 {
+		packageName, version, _ = strings.Cut(args[0], "@")
+	}
+
+	if version == "" {
+		if save {
+			return errors.New("--save requires a version (e.g., jq@1.6)")
+		}
+
+		err = installer.Install(ctx, gCtx, packageName, reporter)
+		if err != nil {
+			return fmt.Errorf("error installing: %w", err)
+		}
+
+		return nil
+	}
+
+	err = installer.InstallVersion(ctx, gCtx, packageName, version, reporter)
+	if err != nil {
+		return fmt.Errorf("error installing: %w", err)
+	}
+
+	if save {
+		err = gCtx.AddPackageToConfig(packageName, version)
+		if err != nil {
+			return fmt.Errorf("error saving version to config: %w", err)
+		}
+
+		fmt.Fprintf(reporter, "Saved %s@%s to config\n", packageName, version)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/noizwaves/grab/pkg"
+	"github.com/spf13/cobra"
+)
+
+// outdatedExitCode is returned when at least one package has a newer release.
+const outdatedExitCode = 10
+
+func makeOutdatedCommand() *cobra.Command {
+	var format string
+
+	outdatedCmd := &cobra.Command{
+		Use:   "outdated [PACKAGE_NAME]",
+		Short: "Reports packages with newer remote versions",
+		Long: `
+Reports packages with newer remote versions, without modifying the config file.
+Defaults to checking all packages.
+
+Exits with status 0 when all packages are current, and 10 when any package is outdated.
+Packages whose newer release is held back by minReleaseAge are reported as pending, and do not count as outdated.
+
+Arguments:
+  PACKAGE_NAME (optional): Name of the package to check (e.g., "fzf")
+
+Flags:
+  -o, --output string: Report format, one of text, json or markdown (default "text")
+`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		PreRun: func(_ *cobra.Command, _ []string) {
+			err := configureLogging()
+			cobra.CheckErr(err)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := pkg.ValidateReportFormat(format)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := newCommandContext(cmd)
+			defer cancel()
+
+			gCtx, err := newGrabContext()
+			if err != nil {
+				return fmt.Errorf("error loading context: %w", err)
+			}
+
+			updater := pkg.Updater{
+				Sources: newSources(gCtx),
+			}
+
+			var packageName string
+			if len(args) > 0 {
+				packageName = args[0]
+			}
+
+			statuses, err := updater.Check(ctx, gCtx, packageName)
+			if err != nil {
+				return fmt.Errorf("error checking for updates: %w", err)
+			}
+
+			err = pkg.WriteStatusReport(statuses, format, os.Stdout)
+			if err != nil {
+				return fmt.Errorf("error writing report: %w", err)
+			}
+
+			if count := pkg.CountOutdated(statuses); count > 0 {
+				// The report already lists the outdated packages, so the exit code alone signals them
+				cmd.SilenceErrors = true
+
+				return &exitCodeError{
+					code:    outdatedExitCode,
+					message: fmt.Sprintf("%d package(s) outdated", count),
+				}
+			}
+
+			return nil
+		},
+	}
+
+	outdatedCmd.Flags().StringVarP(
+		&format, "output", "o", pkg.ReportFormatText,
+		"Report format, one of text, json or markdown",
+	)
+
+	return outdatedCmd
+}
@@ -1,15 +1,33 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
+	"github.com/noizwaves/grab/pkg/progress"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// exitCodeError terminates grab with a specific exit code.
+type exitCodeError struct {
+	code    int
+	message string
+}
+
+func (e *exitCodeError) Error() string {
+	return e.message
+}
+
+// logOptions configure the default logger, for commands that route logs through a progress reporter.
+var logOptions slog.HandlerOptions //nolint:gochecknoglobals
+
 func configureLogging() error {
 	opts := slog.HandlerOptions{}
 
@@ -29,12 +47,28 @@ func configureLogging() error {
 		return fmt.Errorf("invalid log level %q", logLevel)
 	}
 
+	logOptions = opts
+
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &opts))
 	slog.SetDefault(logger)
 
 	return nil
 }
 
+// newReporter creates a progress reporter on stdout and routes logs through it, so that they do not break its
+// status line. The returned function stops reporting and restores the logger.
+func newReporter() (*progress.Reporter, func()) {
+	reporter := progress.NewReporter(os.Stdout, progress.IsTerminal(os.Stdout))
+
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(reporter.LogWriter(), &logOptions)))
+
+	return reporter, func() {
+		slog.SetDefault(logger)
+		reporter.Close()
+	}
+}
+
 func makeRootCommand() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "grab",
@@ -48,6 +82,13 @@ Supports macOS, Linux, and containers.
 	rootCmd.PersistentFlags().String("log-level", "warn", "Logging level (i.e. debug, info, warn, error) (GRAB_LOG_LEVEL)")
 	rootCmd.PersistentFlags().String("config-path", "", "Config dir (GRAB_CONFIG_PATH) (default \"~/.grab\")")
 	rootCmd.PersistentFlags().String("bin-path", "", "Dir to install binaries (GRAB_BIN_PATH) (default \"~/.local/bin\")")
+	rootCmd.PersistentFlags().Duration(
+		"timeout", 0, "Abort after this long, e.g. 10m (GRAB_TIMEOUT) (default no limit)",
+	)
+	rootCmd.PersistentFlags().Bool(
+		"wait-for-rate-limit", false,
+		"Wait for an exhausted GitHub API rate limit to reset instead of failing (GRAB_WAIT_FOR_RATE_LIMIT)",
+	)
 
 	viper.BindPFlag("log-level", rootCmd.PersistentFlags().Lookup("log-level")) //nolint:errcheck
 	viper.SetDefault("log-level", "warn")
@@ -58,12 +99,19 @@ Supports macOS, Linux, and containers.
 	viper.BindPFlag("bin-path", rootCmd.PersistentFlags().Lookup("bin-path")) //nolint:errcheck
 	viper.SetDefault("bin-path", "")
 
+	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout")) //nolint:errcheck
+	viper.SetDefault("timeout", 0)
+
+	viper.BindPFlag("wait-for-rate-limit", rootCmd.PersistentFlags().Lookup("wait-for-rate-limit")) //nolint:errcheck
+	viper.SetDefault("wait-for-rate-limit", false)
+
 	viper.SetEnvPrefix("grab")
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	viper.AutomaticEnv()
 
 	rootCmd.AddCommand(makeInstallCommand())
 	rootCmd.AddCommand(makeUpdateCommand())
+	rootCmd.AddCommand(makeOutdatedCommand())
 	rootCmd.AddCommand(makeImportCommand())
 	rootCmd.AddCommand(makeGetCommand())
 	rootCmd.AddCommand(makeVersionCommand())
@@ -71,11 +119,33 @@ Supports macOS, Linux, and containers.
 	return rootCmd
 }
 
+// newCommandContext returns the context for a command's work, bounded by --timeout when set.
+func newCommandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
 func Execute() {
 	rootCmd := makeRootCommand()
 
-	err := rootCmd.Execute()
+	// Cancel in-flight work on Ctrl-C, so that it can clean up after itself
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	err := rootCmd.ExecuteContext(ctx)
+
+	stop()
+
 	if err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+
 		os.Exit(1)
 	}
 }
@@ -5,11 +5,12 @@ import (
 	"os"
 
 	"github.com/noizwaves/grab/pkg"
-	"github.com/noizwaves/grab/pkg/github"
 	"github.com/spf13/cobra"
 )
 
 func makeUpdateCommand() *cobra.Command {
+	var showChangelog bool
+
 	updateCmd := &cobra.Command{
 		Use:   "update [PACKAGE_NAME]",
 		Short: "Updates packages to use latest remote version",
@@ -18,6 +19,9 @@ Updates packages to use latest remote version. Defaults to updating all packages
 
 Arguments:
   PACKAGE_NAME (optional): Name of the package to update (e.g., "fzf")
+
+Flags:
+  --changelog: Print the release notes of every release between the current and new versions
 `,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
@@ -25,14 +29,18 @@ Arguments:
 			err := configureLogging()
 			cobra.CheckErr(err)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := newCommandContext(cmd)
+			defer cancel()
+
 			gCtx, err := newGrabContext()
 			if err != nil {
 				return fmt.Errorf("error loading context: %w", err)
 			}
 
 			updater := pkg.Updater{
-				GitHubClient: github.NewClient(),
+				Sources:       newSources(gCtx),
+				ShowChangelog: showChangelog,
 			}
 
 			var packageName string
@@ -40,7 +48,7 @@ Arguments:
 				packageName = args[0]
 			}
 
-			err = updater.Update(gCtx, packageName, os.Stdout)
+			err = updater.Update(ctx, gCtx, packageName, os.Stdout)
 			if err != nil {
 				return fmt.Errorf("error upgrading: %w", err)
 			}
@@ -49,5 +57,10 @@ Arguments:
 		},
 	}
 
+	updateCmd.Flags().BoolVar(
+		&showChangelog, "changelog", false,
+		"Print the release notes of every release between the current and new versions",
+	)
+
 	return updateCmd
 }
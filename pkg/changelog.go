@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxReleaseNoteLines limits how much of each release body is printed.
+	maxReleaseNoteLines = 20
+	releaseNoteIndent   = "    "
+)
+
+var (
+	markdownHeadingRegex = regexp.MustCompile(`^#{1,6}\s+`)
+	markdownBulletRegex  = regexp.MustCompile(`^(\s*)[*+]\s+`)
+	htmlCommentRegex     = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// releasesBetween returns the releases newer than fromVersion, up to and including toVersion, newest first.
+// Releases are expected to be ordered newest first, as returned by sources. Complete is false when fromVersion
+// was not among them, such as when sources only list recent releases, so that older releases may be missing.
+func releasesBetween(binary *Binary, releases []Release, fromVersion, toVersion string) ([]Release, bool) {
+	var output []Release
+
+	collecting := false
+
+	for _, release := range releases {
+		if release.Prerelease {
+			continue
+		}
+
+		version, err := extractReleaseVersion(binary, &release)
+		if err != nil {
+			continue
+		}
+
+		if version == fromVersion {
+			return output, true
+		}
+
+		if version == toVersion {
+			collecting = true
+		}
+
+		if collecting {
+			output = append(output, release)
+		}
+	}
+
+	return output, false
+}
+
+// renderReleaseNotes converts a Markdown release body into indented plain text suitable for a terminal.
+func renderReleaseNotes(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = htmlCommentRegex.ReplaceAllString(body, "")
+	body = strings.TrimSpace(body)
+
+	if body == "" {
+		return releaseNoteIndent + "(no release notes)"
+	}
+
+	lines := make([]string, 0)
+	previousBlank := false
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, " \t")
+		line = markdownHeadingRegex.ReplaceAllString(line, "")
+		line = markdownBulletRegex.ReplaceAllString(line, "$1- ")
+		line = strings.ReplaceAll(line, "**", "")
+
+		blank := line == ""
+		if blank && previousBlank {
+			continue
+		}
+
+		previousBlank = blank
+
+		lines = append(lines, line)
+	}
+
+	truncated := 0
+	if len(lines) > maxReleaseNoteLines {
+		truncated = len(lines) - maxReleaseNoteLines
+		lines = lines[:maxReleaseNoteLines]
+	}
+
+	var output strings.Builder
+
+	for idx, line := range lines {
+		if idx > 0 {
+			output.WriteString("\n")
+		}
+
+		if line != "" {
+			output.WriteString(releaseNoteIndent + line)
+		}
+	}
+
+	if truncated > 0 {
+		fmt.Fprintf(&output, "\n%s... (%d more lines)", releaseNoteIndent, truncated)
+	}
+
+	return output.String()
+}
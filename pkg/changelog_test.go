@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderReleaseNotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "Empty",
+			body:     "  \r\n ",
+			expected: "    (no release notes)",
+		},
+		{
+			name:     "PlainText",
+			body:     "Fixed a bug\r\n",
+			expected: "    Fixed a bug",
+		},
+		{
+			name:     "Markdown",
+			body:     "## What's Changed\n\n\n* **New** feature\n  + nested item\n<!-- hidden -->",
+			expected: "    What's Changed\n\n    - New feature\n      - nested item",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, renderReleaseNotes(testCase.body))
+		})
+	}
+
+	t.Run("Truncated", func(t *testing.T) {
+		body := strings.Repeat("line\n", maxReleaseNoteLines+5)
+
+		result := renderReleaseNotes(body)
+
+		assert.Equal(t, maxReleaseNoteLines+1, strings.Count(result, "\n")+1)
+		assert.True(t, strings.HasSuffix(result, "    ... (5 more lines)"))
+	})
+}
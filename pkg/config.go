@@ -3,18 +3,265 @@ package pkg
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/noizwaves/grab/pkg/gitea"
+	"github.com/noizwaves/grab/pkg/github"
+	"github.com/noizwaves/grab/pkg/gitlab"
+	"github.com/noizwaves/grab/pkg/transport"
 	yaml "gopkg.in/yaml.v3"
 )
 
 type configRoot struct {
 	Packages map[string]string `yaml:"packages"`
+	Settings ConfigSettings    `yaml:"settings,omitempty"`
+}
+
+type ConfigSettings struct {
+	// MinReleaseAge is the default minimum age of a release before it is adopted (e.g. 72h).
+	MinReleaseAge string `yaml:"minReleaseAge,omitempty"`
+
+	// GitHubHosts configures github.com and GitHub Enterprise Server instances, keyed by host name.
+	GitHubHosts map[string]ConfigGitHubHost `yaml:"gitHubHosts,omitempty"`
+
+	// Mirrors rewrite the URLs of GitHub requests, such as to route downloads through an artifact proxy.
+	Mirrors []ConfigMirror `yaml:"mirrors,omitempty"`
+
+	// Retry configures how GitHub requests failing with network or server errors are retried.
+	Retry *ConfigRetry `yaml:"retry,omitempty"`
+
+	// Transport configures the certificates, timeouts and proxy used for all requests.
+	Transport *ConfigTransport `yaml:"transport,omitempty"`
+}
+
+// ConfigTransport configures how connections are made. Each field can be overridden by an environment variable.
+type ConfigTransport struct {
+	// CABundles are PEM files of certificate authorities to trust in addition to the system's (GRAB_CA_BUNDLE)
+	CABundles []string `yaml:"caBundles,omitempty"`
+
+	// ClientCert and ClientKey are PEM files for mutual TLS (GRAB_CLIENT_CERT and GRAB_CLIENT_KEY)
+	ClientCert string `yaml:"clientCert,omitempty"`
+	ClientKey  string `yaml:"clientKey,omitempty"`
+
+	// ConnectTimeout bounds establishing a connection (GRAB_CONNECT_TIMEOUT, default: 30s)
+	ConnectTimeout string `yaml:"connectTimeout,omitempty"`
+
+	// ReadTimeout bounds waiting for data from a server (GRAB_READ_TIMEOUT, default: 60s)
+	ReadTimeout string `yaml:"readTimeout,omitempty"`
+
+	// Proxy is the URL of the proxy for all requests (GRAB_PROXY, default: HTTPS_PROXY and related variables)
+	Proxy string `yaml:"proxy,omitempty"`
+}
+
+// ConfigRetry overrides the default retry behaviour. Durations are given as strings (e.g. 500ms).
+type ConfigRetry struct {
+	Attempts       int    `yaml:"attempts,omitempty"`
+	InitialBackoff string `yaml:"initialBackoff,omitempty"`
+	MaxBackoff     string `yaml:"maxBackoff,omitempty"`
+	Timeout        string `yaml:"timeout,omitempty"`
+}
+
+// ConfigMirror redirects URLs beginning with From to the same path beneath To.
+type ConfigMirror struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+
+	// TokenEnvVar names the environment variable holding a bearer token for the mirror (default: netrc credentials)
+	TokenEnvVar string `yaml:"tokenEnvVar,omitempty"`
+}
+
+// ConfigGitHubHost overrides how github.com or a GitHub Enterprise Server instance is reached.
+// Empty fields fall back to the defaults for the host.
+type ConfigGitHubHost struct {
+	// APIURL is the root of the REST API (default: https://<host>/api/v3)
+	APIURL string `yaml:"apiURL,omitempty"`
+
+	// DownloadURL is the root of release asset downloads (default: https://<host>)
+	DownloadURL string `yaml:"downloadURL,omitempty"`
+
+	// TokenEnvVar names an environment variable holding the access token, checked ahead of the defaults
+	TokenEnvVar string `yaml:"tokenEnvVar,omitempty"`
+
+	// Token is the access token for the host, used ahead of any other source
+	Token string `yaml:"token,omitempty"`
+}
+
+// IsGitHubHost reports whether host is github.com or a configured GitHub Enterprise Server instance.
+func (s *ConfigSettings) IsGitHubHost(host string) bool {
+	if host == github.DefaultHost {
+		return true
+	}
+
+	_, ok := s.GitHubHosts[host]
+
+	return ok
+}
+
+// RetryConfig returns the default retry behaviour with any configured overrides.
+func (s *ConfigSettings) RetryConfig() (github.RetryConfig, error) {
+	config := github.DefaultRetryConfig()
+
+	if s.Retry == nil {
+		return config, nil
+	}
+
+	if s.Retry.Attempts < 0 {
+		return config, fmt.Errorf("retry attempts cannot be negative, got %d", s.Retry.Attempts)
+	}
+
+	if s.Retry.Attempts > 0 {
+		config.MaxAttempts = s.Retry.Attempts
+	}
+
+	err := parseDurationSettings([]durationSetting{
+		{"retry initial backoff", s.Retry.InitialBackoff, &config.InitialBackoff},
+		{"retry max backoff", s.Retry.MaxBackoff, &config.MaxBackoff},
+		{"retry timeout", s.Retry.Timeout, &config.AttemptTimeout},
+	})
+
+	return config, err
+}
+
+// TransportConfig returns how connections are made, applying the transport settings and then environment variables.
+func (s *ConfigSettings) TransportConfig() (transport.Config, error) {
+	config := transport.DefaultConfig()
+
+	settings := ConfigTransport{}
+	if s.Transport != nil {
+		settings = *s.Transport
+	}
+
+	if value := os.Getenv("GRAB_CA_BUNDLE"); value != "" {
+		settings.CABundles = filepath.SplitList(value)
+	}
+
+	overrides := []struct {
+		envVar string
+		target *string
+	}{
+		{"GRAB_CLIENT_CERT", &settings.ClientCert},
+		{"GRAB_CLIENT_KEY", &settings.ClientKey},
+		{"GRAB_CONNECT_TIMEOUT", &settings.ConnectTimeout},
+		{"GRAB_READ_TIMEOUT", &settings.ReadTimeout},
+		{"GRAB_PROXY", &settings.Proxy},
+	}
+
+	for _, override := range overrides {
+		if value := os.Getenv(override.envVar); value != "" {
+			*override.target = value
+		}
+	}
+
+	config.CABundles = settings.CABundles
+	config.ClientCert = settings.ClientCert
+	config.ClientKey = settings.ClientKey
+	config.Proxy = settings.Proxy
+
+	err := parseDurationSettings([]durationSetting{
+		{"connect timeout", settings.ConnectTimeout, &config.ConnectTimeout},
+		{"read timeout", settings.ReadTimeout, &config.ReadTimeout},
+	})
+
+	return config, err
+}
+
+type durationSetting struct {
+	name   string
+	value  string
+	target *time.Duration
+}
+
+// parseDurationSettings parses the settings that are set into their targets.
+func parseDurationSettings(settings []durationSetting) error {
+	for _, setting := range settings {
+		if setting.value == "" {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(setting.value)
+		if err != nil {
+			return fmt.Errorf("%s is not a valid duration: %w", setting.name, err)
+		}
+
+		*setting.target = parsed
+	}
+
+	return nil
+}
+
+// GitHubClientConfig returns how to reach github.com or the GitHub Enterprise Server instance at host.
+// Settings are expected to have been validated when the context was loaded.
+func (s *ConfigSettings) GitHubClientConfig(host string) github.ClientConfig {
+	config := github.DefaultClientConfig()
+	if host != github.DefaultHost {
+		config = github.EnterpriseClientConfig(host)
+	}
+
+	s.applyClientSettings(&config)
+
+	overrides, ok := s.GitHubHosts[host]
+	if !ok {
+		return config
+	}
+
+	if overrides.APIURL != "" {
+		config.BaseURL = strings.TrimSuffix(overrides.APIURL, "/")
+		config.GraphQLURL = github.GraphQLURL(config.BaseURL)
+	}
+
+	if overrides.DownloadURL != "" {
+		config.DownloadBaseURL = strings.TrimSuffix(overrides.DownloadURL, "/")
+	}
+
+	if overrides.TokenEnvVar != "" {
+		config.Credentials.EnvVars = append([]string{overrides.TokenEnvVar}, config.Credentials.EnvVars...)
+	}
+
+	config.Credentials.Token = overrides.Token
+
+	return config
+}
+
+// GiteaClientConfig returns how to reach the Gitea or Forgejo instance at host.
+func (s *ConfigSettings) GiteaClientConfig(host string) github.ClientConfig {
+	config := gitea.ClientConfig(host)
+
+	s.applyClientSettings(&config)
+
+	return config
+}
+
+// GitLabClientConfig returns how to reach the GitLab instance at host.
+func (s *ConfigSettings) GitLabClientConfig(host string) gitlab.ClientConfig {
+	config := gitlab.DefaultClientConfig(host)
+
+	if retry, err := s.RetryConfig(); err == nil {
+		config.Retry = retry
+	}
+
+	return config
+}
+
+// applyClientSettings applies the retry and mirror settings shared by GitHub compatible clients.
+func (s *ConfigSettings) applyClientSettings(config *github.ClientConfig) {
+	if retry, err := s.RetryConfig(); err == nil {
+		config.Retry = retry
+	}
+
+	for _, mirror := range s.Mirrors {
+		config.URLRewrites = append(config.URLRewrites, github.URLRewrite{
+			From:        mirror.From,
+			To:          mirror.To,
+			TokenEnvVar: mirror.TokenEnvVar,
+		})
+	}
 }
 
 type repository struct {
@@ -33,11 +280,76 @@ type ConfigPackageMetadata struct {
 }
 
 type ConfigPackageSpec struct {
-	GitHubRelease ConfigGitHubRelease `yaml:"gitHubRelease"`
-	Program       ConfigProgram       `yaml:"program"`
+	GitHubRelease *ConfigGitHubRelease `yaml:"gitHubRelease,omitempty"`
+	GitLabRelease *ConfigGitLabRelease `yaml:"gitLabRelease,omitempty"`
+	GiteaRelease  *ConfigGiteaRelease  `yaml:"giteaRelease,omitempty"`
+	HTTP          *ConfigHTTP          `yaml:"http,omitempty"`
+	OCI           *ConfigOCI           `yaml:"oci,omitempty"`
+	File          *ConfigFile          `yaml:"file,omitempty"`
+	Program       ConfigProgram        `yaml:"program"`
+	MinReleaseAge string               `yaml:"minReleaseAge,omitempty"`
+}
+
+// SourceKind determines which source the package is published through.
+// Exactly one source must be configured.
+func (s *ConfigPackageSpec) SourceKind() (string, error) {
+	kinds := make([]string, 0, 1)
+
+	if s.GitHubRelease != nil {
+		kinds = append(kinds, SourceKindGitHub)
+	}
+
+	if s.GitLabRelease != nil {
+		kinds = append(kinds, SourceKindGitLab)
+	}
+
+	if s.GiteaRelease != nil {
+		kinds = append(kinds, SourceKindGitea)
+	}
+
+	if s.HTTP != nil {
+		kinds = append(kinds, SourceKindHTTP)
+	}
+
+	if s.OCI != nil {
+		kinds = append(kinds, SourceKindOCI)
+	}
+
+	if s.File != nil {
+		kinds = append(kinds, SourceKindFile)
+	}
+
+	switch len(kinds) {
+	case 0:
+		return "", errors.New("no source configured")
+	case 1:
+		return kinds[0], nil
+	default:
+		return "", fmt.Errorf("multiple sources configured: %s", strings.Join(kinds, ", "))
+	}
 }
 
 type ConfigGitHubRelease struct {
+	Host               string            `yaml:"host,omitempty"`
+	Org                string            `yaml:"org"`
+	Repo               string            `yaml:"repo"`
+	Name               string            `yaml:"name"`
+	VersionRegex       string            `yaml:"versionRegex"`
+	FileName           map[string]string `yaml:"fileName"`
+	EmbeddedBinaryPath map[string]string `yaml:"embeddedBinaryPath,omitempty"`
+}
+
+type ConfigGitLabRelease struct {
+	Host               string            `yaml:"host,omitempty"`
+	Project            string            `yaml:"project"`
+	Name               string            `yaml:"name"`
+	VersionRegex       string            `yaml:"versionRegex"`
+	FileName           map[string]string `yaml:"fileName"`
+	EmbeddedBinaryPath map[string]string `yaml:"embeddedBinaryPath,omitempty"`
+}
+
+type ConfigGiteaRelease struct {
+	Host               string            `yaml:"host"`
 	Org                string            `yaml:"org"`
 	Repo               string            `yaml:"repo"`
 	Name               string            `yaml:"name"`
@@ -46,6 +358,38 @@ type ConfigGitHubRelease struct {
 	EmbeddedBinaryPath map[string]string `yaml:"embeddedBinaryPath,omitempty"`
 }
 
+type ConfigHTTP struct {
+	URL                map[string]string    `yaml:"url"`
+	VersionRegex       string               `yaml:"versionRegex"`
+	LatestVersion      *ConfigLatestVersion `yaml:"latestVersion,omitempty"`
+	ChecksumURL        map[string]string    `yaml:"checksumURL,omitempty"`
+	EmbeddedBinaryPath map[string]string    `yaml:"embeddedBinaryPath,omitempty"`
+}
+
+type ConfigOCI struct {
+	Registry           string            `yaml:"registry"`
+	Repository         string            `yaml:"repository"`
+	Tag                string            `yaml:"tag"`
+	VersionRegex       string            `yaml:"versionRegex"`
+	FileName           map[string]string `yaml:"fileName,omitempty"`
+	EmbeddedBinaryPath map[string]string `yaml:"embeddedBinaryPath,omitempty"`
+}
+
+type ConfigFile struct {
+	Path               string            `yaml:"path"`
+	Org                string            `yaml:"org"`
+	Repo               string            `yaml:"repo"`
+	Name               string            `yaml:"name"`
+	VersionRegex       string            `yaml:"versionRegex"`
+	FileName           map[string]string `yaml:"fileName"`
+	EmbeddedBinaryPath map[string]string `yaml:"embeddedBinaryPath,omitempty"`
+}
+
+type ConfigLatestVersion struct {
+	URL      string `yaml:"url"`
+	JSONPath string `yaml:"jsonPath,omitempty"`
+}
+
 type ConfigProgram struct {
 	VersionArgs  []string `yaml:"versionArgs,flow"`
 	VersionRegex string   `yaml:"versionRegex"`
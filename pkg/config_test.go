@@ -2,10 +2,15 @@ package pkg
 
 import (
 	"path"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/noizwaves/grab/pkg/github"
 	"github.com/noizwaves/grab/pkg/internal/asserth"
+	"github.com/noizwaves/grab/pkg/transport"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadRepositoryValid(t *testing.T) {
@@ -20,7 +25,7 @@ func TestLoadRepositoryValid(t *testing.T) {
 					Name: "bar",
 				},
 				Spec: ConfigPackageSpec{
-					GitHubRelease: ConfigGitHubRelease{
+					GitHubRelease: &ConfigGitHubRelease{
 						Org:          "foo",
 						Repo:         "bar",
 						Name:         "{{ .Version }}",
@@ -46,7 +51,7 @@ func TestLoadRepositoryValid(t *testing.T) {
 					Name: "baz",
 				},
 				Spec: ConfigPackageSpec{
-					GitHubRelease: ConfigGitHubRelease{
+					GitHubRelease: &ConfigGitHubRelease{
 						Org:          "foo",
 						Repo:         "baz",
 						Name:         "v{{ .Version }}",
@@ -126,3 +131,180 @@ func TestSaveConfig(t *testing.T) {
 		"  baz: 0.16.5\n"
 	asserth.FileContents(t, actualPath, expectedContent)
 }
+
+func TestConfigSettingsGitHubHosts(t *testing.T) {
+	config, err := loadConfig("testdata/configs/github-hosts.yml")
+	require.NoError(t, err)
+
+	settings := config.Settings
+
+	t.Run("IsGitHubHost", func(t *testing.T) {
+		assert.True(t, settings.IsGitHubHost("github.com"))
+		assert.True(t, settings.IsGitHubHost("ghe.corp"))
+		assert.True(t, settings.IsGitHubHost("github.example.com"))
+		assert.False(t, settings.IsGitHubHost("gitlab.com"))
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		expected := github.ClientConfig{
+			BaseURL:         "https://ghe-api.corp/api/v3",
+			GraphQLURL:      "https://ghe-api.corp/api/graphql",
+			DownloadBaseURL: "https://ghe.corp",
+			Credentials: github.Credentials{
+				Host:    "ghe.corp",
+				Token:   "settings-token",
+				EnvVars: []string{"CORP_GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"},
+			},
+			AuthScheme: "Bearer",
+			AssetsAPI:  true,
+			Retry:      github.DefaultRetryConfig(),
+		}
+
+		assert.Equal(t, expected, settings.GitHubClientConfig("ghe.corp"))
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		expected := github.ClientConfig{
+			BaseURL:         "https://github.example.com/api/v3",
+			GraphQLURL:      "https://github.example.com/api/graphql",
+			DownloadBaseURL: "https://github.example.com",
+			Credentials: github.Credentials{
+				Host:    "github.example.com",
+				EnvVars: []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"},
+			},
+			AuthScheme: "Bearer",
+			AssetsAPI:  true,
+			Retry:      github.DefaultRetryConfig(),
+		}
+
+		assert.Equal(t, expected, settings.GitHubClientConfig("github.example.com"))
+	})
+}
+
+func TestConfigSettingsMirrors(t *testing.T) {
+	config, err := loadConfig("testdata/configs/mirrors.yml")
+	require.NoError(t, err)
+
+	expected := []github.URLRewrite{
+		{From: "https://github.com/", To: "https://artifactory.corp/github/"},
+		{From: "https://api.github.com/", To: "https://artifactory.corp/api/github/", TokenEnvVar: "ARTIFACTORY_TOKEN"},
+	}
+
+	actual := config.Settings.GitHubClientConfig("github.com")
+
+	assert.Equal(t, "https://api.github.com", actual.BaseURL)
+	assert.Equal(t, expected, actual.URLRewrites)
+}
+
+func TestConfigSettingsGiteaClientConfig(t *testing.T) {
+	settings := ConfigSettings{
+		Retry:   &ConfigRetry{Attempts: 5},
+		Mirrors: []ConfigMirror{{From: "https://codeberg.org/", To: "https://artifactory.corp/codeberg/"}},
+	}
+
+	actual := settings.GiteaClientConfig("codeberg.org")
+
+	assert.Equal(t, "https://codeberg.org/api/v1", actual.BaseURL)
+	assert.Equal(t, 5, actual.Retry.MaxAttempts)
+	assert.Equal(t, []github.URLRewrite{
+		{From: "https://codeberg.org/", To: "https://artifactory.corp/codeberg/"},
+	}, actual.URLRewrites)
+}
+
+func TestConfigSettingsRetryConfig(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		settings := ConfigSettings{}
+
+		actual, err := settings.RetryConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, github.DefaultRetryConfig(), actual)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		settings := ConfigSettings{
+			Retry: &ConfigRetry{Attempts: 5, MaxBackoff: "10s", Timeout: "2m"},
+		}
+
+		actual, err := settings.RetryConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, github.RetryConfig{
+			MaxAttempts:    5,
+			InitialBackoff: time.Second,
+			MaxBackoff:     10 * time.Second,
+			AttemptTimeout: 2 * time.Minute,
+		}, actual)
+	})
+
+	t.Run("InvalidDuration", func(t *testing.T) {
+		settings := ConfigSettings{
+			Retry: &ConfigRetry{InitialBackoff: "soon"},
+		}
+
+		_, err := settings.RetryConfig()
+
+		assert.ErrorContains(t, err, "retry initial backoff is not a valid duration")
+	})
+}
+
+func TestConfigSettingsTransportConfig(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		settings := ConfigSettings{}
+
+		actual, err := settings.TransportConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, transport.DefaultConfig(), actual)
+	})
+
+	t.Run("Settings", func(t *testing.T) {
+		settings := ConfigSettings{
+			Transport: &ConfigTransport{
+				CABundles:   []string{"/etc/corp/ca.pem"},
+				ReadTimeout: "2m",
+				Proxy:       "http://proxy.corp:3128",
+			},
+		}
+
+		actual, err := settings.TransportConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, transport.Config{
+			CABundles:      []string{"/etc/corp/ca.pem"},
+			ConnectTimeout: transport.DefaultConnectTimeout,
+			ReadTimeout:    2 * time.Minute,
+			Proxy:          "http://proxy.corp:3128",
+		}, actual)
+	})
+
+	t.Run("EnvironmentOverrides", func(t *testing.T) {
+		t.Setenv("GRAB_CA_BUNDLE", "/tmp/one.pem"+string(filepath.ListSeparator)+"/tmp/two.pem")
+		t.Setenv("GRAB_CONNECT_TIMEOUT", "5s")
+		t.Setenv("GRAB_PROXY", "http://env-proxy:8080")
+
+		settings := ConfigSettings{
+			Transport: &ConfigTransport{
+				CABundles: []string{"/etc/corp/ca.pem"},
+				Proxy:     "http://proxy.corp:3128",
+			},
+		}
+
+		actual, err := settings.TransportConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/tmp/one.pem", "/tmp/two.pem"}, actual.CABundles)
+		assert.Equal(t, 5*time.Second, actual.ConnectTimeout)
+		assert.Equal(t, "http://env-proxy:8080", actual.Proxy)
+	})
+
+	t.Run("InvalidDuration", func(t *testing.T) {
+		settings := ConfigSettings{
+			Transport: &ConfigTransport{ConnectTimeout: "forever"},
+		}
+
+		_, err := settings.TransportConfig()
+
+		assert.ErrorContains(t, err, "connect timeout is not a valid duration")
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"slices"
+
+	"github.com/noizwaves/grab/pkg/transport"
 )
 
 const (
@@ -18,6 +22,10 @@ const (
 
 	configFileName    = "config.yml"
 	repositoryDirName = "repository"
+	cacheDirName      = "cache"
+
+	// userCacheDirName is the directory within the user's cache directory (e.g. ~/.cache/grab).
+	userCacheDirName = "grab"
 )
 
 type GrabContext struct {
@@ -26,6 +34,8 @@ type GrabContext struct {
 	ConfigPath   string
 	Config       *configRoot
 	RepoPath     string
+	CacheDir     string
+	HTTPClient   *http.Client
 	Platform     string
 	Architecture string
 }
@@ -58,6 +68,26 @@ func NewGrabContext(configPathOverride, binPathOverride string) (*GrabContext, e
 		return nil, fmt.Errorf("error loading repository: %w", err)
 	}
 
+	defaultMinReleaseAge, err := parseMinReleaseAge(config.Settings.MinReleaseAge)
+	if err != nil {
+		return nil, fmt.Errorf("error loading settings: %w", err)
+	}
+
+	_, err = config.Settings.RetryConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading settings: %w", err)
+	}
+
+	transportConfig, err := config.Settings.TransportConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading settings: %w", err)
+	}
+
+	httpClient, err := transport.NewClient(transportConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading settings: %w", err)
+	}
+
 	binaries := make([]*Binary, 0)
 
 	for name, version := range config.Packages {
@@ -71,6 +101,11 @@ func NewGrabContext(configPathOverride, binPathOverride string) (*GrabContext, e
 			return nil, fmt.Errorf("error constructing binary %q: %w", name, err)
 		}
 
+		// Packages without their own policy inherit the global one
+		if located.Spec.MinReleaseAge == "" {
+			binary.MinReleaseAge = defaultMinReleaseAge
+		}
+
 		binaries = append(binaries, binary)
 	}
 
@@ -80,6 +115,8 @@ func NewGrabContext(configPathOverride, binPathOverride string) (*GrabContext, e
 		ConfigPath:   configFilePath,
 		Config:       config,
 		RepoPath:     repoPath,
+		CacheDir:     getCacheDir(configPath),
+		HTTPClient:   httpClient,
 		Platform:     runtime.GOOS,
 		Architecture: runtime.GOARCH,
 	}, err
@@ -163,6 +200,17 @@ func getConfigDirPath(override string) (string, error) {
 	return configDirPath, nil
 }
 
+// getCacheDir returns the user's cache directory for grab, or one within the config directory when the
+// platform has none.
+func getCacheDir(configPath string) string {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(configPath, cacheDirName)
+	}
+
+	return filepath.Join(userCacheDir, userCacheDirName)
+}
+
 func getBinPath(override string) (string, error) {
 	if override != "" {
 		return override, nil
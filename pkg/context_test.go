@@ -3,11 +3,13 @@ package pkg
 import (
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/noizwaves/grab/pkg/internal/asserth"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func makeEmptyConfig(t *testing.T) string {
@@ -38,6 +40,11 @@ func TestNewGrabContext(t *testing.T) {
 	emptyConfigDir := makeEmptyConfig(t)
 
 	t.Run("NoOverrides", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", "")
+
+		userCacheDir, err := os.UserCacheDir()
+		require.NoError(t, err)
+
 		result, err := NewGrabContext("", "")
 
 		assert.NoError(t, err)
@@ -45,6 +52,7 @@ func TestNewGrabContext(t *testing.T) {
 		assert.Equal(t, result.ConfigPath, path.Join("../testdata/simple/.grab/config.yml"))
 		assert.Equal(t, result.BinPath, path.Join("../testdata/simple/.local/bin"))
 		assert.Equal(t, result.RepoPath, path.Join("../testdata/simple/.grab/repository"))
+		assert.Equal(t, result.CacheDir, filepath.Join(userCacheDir, "grab"))
 		assert.Equal(t, result.Platform, runtime.GOOS)
 		assert.Equal(t, result.Architecture, runtime.GOARCH)
 
@@ -73,6 +81,19 @@ func TestNewGrabContext(t *testing.T) {
 		assert.Len(t, result.Binaries, 0)
 	})
 
+	t.Run("NoUserCacheDir", func(t *testing.T) {
+		t.Setenv("HOME", "")
+		t.Setenv("XDG_CACHE_HOME", "")
+
+		override := path.Join(emptyConfigDir, ".grab")
+
+		result, err := NewGrabContext(override, t.TempDir())
+		assert.NoError(t, err)
+
+		// The cache falls back to the config directory
+		assert.Equal(t, result.CacheDir, filepath.Join(override, "cache"))
+	})
+
 	t.Run("BinOverride", func(t *testing.T) {
 		override := t.TempDir()
 
@@ -0,0 +1,44 @@
+// Package gitea provides clients for the releases API of Gitea and Forgejo instances.
+// The API is compatible with GitHub's, so the clients share the GitHub implementation.
+package gitea
+
+import (
+	"net/http"
+
+	"github.com/noizwaves/grab/pkg/github"
+)
+
+// NewClient creates a client for the Gitea or Forgejo instance at host (e.g. codeberg.org),
+// making requests with httpClient.
+func NewClient(host string, httpClient *http.Client) *github.ClientImpl {
+	config := ClientConfig(host)
+	config.HTTPClient = httpClient
+
+	return github.NewClientWithConfig(config)
+}
+
+// ClientConfig returns the default configuration for the Gitea or Forgejo instance at host,
+// for callers that apply their own settings before creating a client with github.NewClientWithConfig.
+func ClientConfig(host string) github.ClientConfig {
+	return clientConfig("https://" + host)
+}
+
+// NewClientWithBaseURL creates a client for the Gitea or Forgejo instance served from baseURL.
+func NewClientWithBaseURL(baseURL string) *github.ClientImpl {
+	return github.NewClientWithConfig(clientConfig(baseURL))
+}
+
+// clientConfig describes the instance served from baseURL. The token is read from GITEA_TOKEN,
+// and is also sent with downloads, which Gitea authorizes like API requests.
+func clientConfig(baseURL string) github.ClientConfig {
+	return github.ClientConfig{
+		BaseURL:         baseURL + "/api/v1",
+		DownloadBaseURL: baseURL,
+		Credentials: github.Credentials{
+			EnvVars: []string{"GITEA_TOKEN"},
+		},
+		AuthScheme:    "token",
+		DownloadToken: true,
+		Retry:         github.DefaultRetryConfig(),
+	}
+}
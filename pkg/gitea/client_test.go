@@ -0,0 +1,103 @@
+package gitea
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/noizwaves/grab/pkg/github"
+)
+
+func TestGetLatestRelease_Success(t *testing.T) {
+	// Mock Gitea API response
+	mockRelease := github.Release{
+		Name:    "v0.9.0",
+		TagName: "v0.9.0",
+		URL:     "https://forgejo.corp/tools/widget/releases/tag/v0.9.0",
+	}
+
+	// Create mock server
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		// Verify request
+		if request.URL.Path != "/api/v1/repos/tools/widget/releases/latest" {
+			t.Errorf("Expected path '/api/v1/repos/tools/widget/releases/latest', got '%s'", request.URL.Path)
+		}
+
+		if request.Header.Get("Authorization") != "token secret" {
+			t.Errorf("Expected Authorization header 'token secret', got '%s'", request.Header.Get("Authorization"))
+		}
+
+		// Return mock response
+		responseWriter.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(responseWriter).Encode(mockRelease)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITEA_TOKEN", "secret")
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.GetLatestRelease(t.Context(), "tools", "widget")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.TagName != "v0.9.0" {
+		t.Errorf("Expected tag name 'v0.9.0', got '%s'", result.TagName)
+	}
+}
+
+func TestDownloadReleaseAsset_Success(t *testing.T) {
+	// Create mock server
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		// Verify request
+		if request.URL.Path != "/tools/widget/releases/download/v0.9.0/widget-linux-amd64" {
+			t.Errorf("Unexpected download path '%s'", request.URL.Path)
+		}
+
+		_, _ = responseWriter.Write([]byte("asset contents"))
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "tools", "widget", "v0.9.0", "widget-linux-amd64")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "asset contents" {
+		t.Errorf("Expected asset contents, got '%s'", string(result))
+	}
+}
+
+func TestDownloadReleaseAsset_PrivateRepository(t *testing.T) {
+	// Create mock server that only serves the asset to authorized requests
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "token secret" {
+			responseWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = responseWriter.Write([]byte("asset contents"))
+	}))
+	defer server.Close()
+
+	t.Setenv("GITEA_TOKEN", "secret")
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "tools", "widget", "v0.9.0", "widget-linux-amd64")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "asset contents" {
+		t.Errorf("Expected asset contents, got '%s'", string(result))
+	}
+}
@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// responseCache persists API responses alongside their ETags, so that requests can be made conditional.
+// GitHub does not count 304 Not Modified responses against the rate limit.
+type responseCache struct {
+	dir string
+}
+
+type cachedResponse struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+func newResponseCache(dir string) *responseCache {
+	if dir == "" {
+		return nil
+	}
+
+	return &responseCache{dir: dir}
+}
+
+// path returns the cache file of a response, keyed by URL and media type.
+func (c *responseCache) path(url, accept string) string {
+	sum := sha256.Sum256([]byte(accept + " " + url))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached response for url, or nil when there is none.
+func (c *responseCache) load(url, accept string) *cachedResponse {
+	if c == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path(url, accept))
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedResponse
+
+	err = json.Unmarshal(data, &cached)
+	if err != nil || cached.URL != url || cached.ETag == "" {
+		return nil
+	}
+
+	return &cached
+}
+
+// store saves a response for later conditional requests. Failures are logged, as the cache is only an optimization.
+func (c *responseCache) store(ctx context.Context, url, accept, etag string, body []byte) {
+	if c == nil || etag == "" {
+		return
+	}
+
+	err := c.write(c.path(url, accept), cachedResponse{URL: url, ETag: etag, Body: body})
+	if err != nil {
+		slog.WarnContext(ctx, "Unable to cache response", "url", url, "error", err)
+	}
+}
+
+func (c *responseCache) write(path string, cached cachedResponse) error {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("error encoding cached response: %w", err)
+	}
+
+	err = os.MkdirAll(c.dir, 0o700) //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	// Write to a temporary file first, so that concurrent runs never read a partial entry
+	file, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+
+	closeErr := file.Close()
+	if err != nil || closeErr != nil {
+		return fmt.Errorf("error writing cache file: %w", errors.Join(err, closeErr))
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return fmt.Errorf("error saving cache file: %w", err)
+	}
+
+	return nil
+}
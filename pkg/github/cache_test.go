@@ -0,0 +1,80 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetLatestRelease_ConditionalRequest(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	requests := 0
+	notModified := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		requests++
+
+		if request.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+
+			responseWriter.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		responseWriter.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.2.3"})
+	}))
+	defer server.Close()
+
+	config := DefaultClientConfig()
+	config.BaseURL = server.URL
+	config.CacheDir = t.TempDir()
+
+	for range 2 {
+		// A new client for each run, as the cache is persisted on disk
+		client := NewClientWithConfig(config)
+
+		result, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.Name != "v1.2.3" {
+			t.Errorf("Expected release name 'v1.2.3', got '%s'", result.Name)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	if notModified != 1 {
+		t.Errorf("Expected the second request to be conditional, got %d conditional requests", notModified)
+	}
+}
+
+func TestGetLatestRelease_WithoutCache(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("If-None-Match") != "" {
+			t.Errorf("Expected no conditional request, got If-None-Match '%s'", request.Header.Get("If-None-Match"))
+		}
+
+		responseWriter.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.2.3"})
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+
+	for range 2 {
+		_, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
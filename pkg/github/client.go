@@ -1,18 +1,29 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
+	neturl "net/url"
+	"slices"
+	"sync"
+	"time"
 )
 
-type errorBody struct {
-	Message string `json:"message"`
-}
+const (
+	// DefaultHost is the host of github.com, whose API is served from a separate host.
+	DefaultHost = "github.com"
+
+	// releasesPerPage is the maximum page size supported by the GitHub API.
+	releasesPerPage = 100
+
+	// octetStream is the media type requesting an asset's content, rather than its metadata.
+	octetStream = "application/octet-stream"
+)
 
 func parseRelease(data []byte) (*Release, error) {
 	var output Release
@@ -25,133 +36,404 @@ func parseRelease(data []byte) (*Release, error) {
 	return &output, nil
 }
 
-func parseError(data []byte) error {
-	var output errorBody
+func parseReleases(data []byte) ([]Release, error) {
+	var output []Release
 
 	err := json.Unmarshal(data, &output)
 	if err != nil {
-		return fmt.Errorf("error parsing error response as JSON: %w", err)
+		return nil, fmt.Errorf("error parsing response as JSON: %w", err)
 	}
 
-	return fmt.Errorf("%s", output.Message)
+	return output, nil
 }
 
 type Client interface {
-	GetLatestRelease(org, repo string) (*Release, error)
-	GetReleaseByTag(org, repo, tag string) (*Release, error)
-	DownloadReleaseAsset(org, repo, releaseName, assetName string) ([]byte, error)
+	GetLatestRelease(ctx context.Context, org, repo string) (*Release, error)
+	GetReleaseByTag(ctx context.Context, org, repo, tag string) (*Release, error)
+	ListReleases(ctx context.Context, org, repo string) ([]Release, error)
+	DownloadReleaseAsset(ctx context.Context, org, repo, releaseName, assetName string) ([]byte, error)
+}
+
+// AssetClient downloads assets whose IDs are already known, such as from an earlier look up of their release.
+type AssetClient interface {
+	// DownloadReleaseAssetByID downloads an asset as DownloadReleaseAsset does, using id in place of looking up
+	// the release when downloading through the assets API.
+	DownloadReleaseAssetByID(ctx context.Context, org, repo, releaseName, assetName string, id int64) ([]byte, error)
+}
+
+// ClientConfig describes how to reach a GitHub compatible releases API.
+type ClientConfig struct {
+	// BaseURL is the root of the REST API (e.g. https://api.github.com)
+	BaseURL string
+
+	// GraphQLURL is the GraphQL API endpoint, used for batch lookups (empty when unsupported)
+	GraphQLURL string
+
+	// DownloadBaseURL is the root of release asset downloads (e.g. https://github.com)
+	DownloadBaseURL string
+
+	// Credentials locate the access token
+	Credentials Credentials
+
+	// AuthScheme precedes the token in the Authorization header (e.g. Bearer)
+	AuthScheme string
+
+	// AssetsAPI downloads assets through the authenticated release assets API when a token is set,
+	// which is required for private repositories
+	AssetsAPI bool
+
+	// DownloadToken sends the token with downloads served from the API's host, for instances such as Gitea
+	// that authorize release download URLs with it
+	DownloadToken bool
+
+	// URLRewrites redirect API requests and downloads, such as through a mirror
+	URLRewrites []URLRewrite
+
+	// WaitForRateLimit sleeps until an exhausted rate limit resets, rather than failing
+	WaitForRateLimit bool
+
+	// Retry controls how failed requests are retried
+	Retry RetryConfig
+
+	// HTTPClient performs requests (default: http.DefaultClient)
+	HTTPClient *http.Client
+
+	// CacheDir stores release metadata and ETags for conditional requests (empty disables caching)
+	CacheDir string
 }
 
 type ClientImpl struct {
-	baseURL string
+	baseURL         string
+	graphQLURL      string
+	downloadBaseURL string
+	credentials     Credentials
+	authScheme      string
+	assetsAPI       bool
+	downloadToken   bool
+	urlRewrites     []URLRewrite
+
+	// token is resolved from credentials on first use
+	token     string
+	tokenOnce sync.Once
+
+	waitForRateLimitReset bool
+	rateLimitWarning      sync.Once
+
+	httpClient *http.Client
+	retry      RetryConfig
+	cache      *responseCache
+
+	now   func() time.Time
+	sleep func(ctx context.Context, duration time.Duration) error
 }
 
 func NewClient() *ClientImpl {
-	return &ClientImpl{
-		baseURL: "https://api.github.com",
-	}
+	return NewClientWithConfig(DefaultClientConfig())
 }
 
 func NewClientWithBaseURL(baseURL string) *ClientImpl {
+	config := DefaultClientConfig()
+	config.BaseURL = baseURL
+	config.GraphQLURL = GraphQLURL(baseURL)
+
+	return NewClientWithConfig(config)
+}
+
+// DefaultClientConfig returns the configuration for github.com.
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		BaseURL:         "https://api.github.com",
+		GraphQLURL:      "https://api.github.com/graphql",
+		DownloadBaseURL: "https://github.com",
+		Credentials: Credentials{
+			Host:    DefaultHost,
+			EnvVars: []string{"GH_TOKEN", "GITHUB_TOKEN"},
+		},
+		AuthScheme: "Bearer",
+		AssetsAPI:  true,
+		Retry:      DefaultRetryConfig(),
+	}
+}
+
+// NewEnterpriseClient creates a client for the GitHub Enterprise Server instance at host.
+func NewEnterpriseClient(host string) *ClientImpl {
+	return NewClientWithConfig(EnterpriseClientConfig(host))
+}
+
+// EnterpriseClientConfig returns the default configuration for the GitHub Enterprise Server instance at host.
+// The token is read from GH_ENTERPRISE_TOKEN or GITHUB_ENTERPRISE_TOKEN, matching the GitHub CLI.
+func EnterpriseClientConfig(host string) ClientConfig {
+	return ClientConfig{
+		BaseURL:         "https://" + host + "/api/v3",
+		GraphQLURL:      "https://" + host + "/api/graphql",
+		DownloadBaseURL: "https://" + host,
+		Credentials: Credentials{
+			Host:    host,
+			EnvVars: []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"},
+		},
+		AuthScheme: "Bearer",
+		AssetsAPI:  true,
+		Retry:      DefaultRetryConfig(),
+	}
+}
+
+func NewClientWithConfig(config ClientConfig) *ClientImpl {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
 	return &ClientImpl{
-		baseURL: baseURL,
+		baseURL:         config.BaseURL,
+		graphQLURL:      config.GraphQLURL,
+		downloadBaseURL: config.DownloadBaseURL,
+		credentials:     config.Credentials,
+		authScheme:      config.AuthScheme,
+		assetsAPI:       config.AssetsAPI,
+		downloadToken:   config.DownloadToken,
+		urlRewrites:     config.URLRewrites,
+
+		waitForRateLimitReset: config.WaitForRateLimit,
+
+		httpClient: httpClient,
+		retry:      config.Retry,
+		cache:      newResponseCache(config.CacheDir),
+
+		now:   time.Now,
+		sleep: sleepContext,
 	}
 }
 
-func (g *ClientImpl) GetLatestRelease(org, repo string) (*Release, error) {
+func (g *ClientImpl) GetLatestRelease(ctx context.Context, org, repo string) (*Release, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", g.baseURL, org, repo)
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	data, err := g.getAPI(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("error creating GET request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Add("Accept", "application/vnd.github+json")
-	req.Header.Add("X-Github-Api-Version", "2022-11-28")
+	return parseRelease(data)
+}
 
-	if token := os.Getenv("GH_TOKEN"); token != "" {
-		req.Header.Add("Authorization", "Bearer "+token)
-	}
+func (g *ClientImpl) GetReleaseByTag(ctx context.Context, org, repo, tag string) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", g.baseURL, org, repo, tag)
 
-	resp, err := http.DefaultClient.Do(req)
+	data, err := g.getAPI(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("error executing request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
+	return parseRelease(data)
+}
+
+// ListReleases returns the most recent releases of a repository, newest first.
+// Only the first page of results is requested.
+func (g *ClientImpl) ListReleases(ctx context.Context, org, repo string) ([]Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=%d", g.baseURL, org, repo, releasesPerPage)
+
+	data, err := g.getAPI(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, err
 	}
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		return parseRelease(data)
-	default:
-		return nil, parseError(data)
-	}
+	return parseReleases(data)
 }
 
-func (g *ClientImpl) GetReleaseByTag(org, repo, tag string) (*Release, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", g.baseURL, org, repo, tag)
+// getAPI performs a GET request against the GitHub API and returns the body of a successful response.
+func (g *ClientImpl) getAPI(ctx context.Context, url string) ([]byte, error) {
+	return g.getAPIWithAccept(ctx, url, "application/vnd.github+json")
+}
+
+func (g *ClientImpl) getAPIWithAccept(ctx context.Context, url, accept string) ([]byte, error) {
+	// Only metadata is cached, not asset downloads
+	cacheable := accept != octetStream
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	var cached *cachedResponse
+	if cacheable {
+		cached = g.cache.load(url, accept)
+	}
+
+	for {
+		etag := ""
+		if cached != nil {
+			etag = cached.ETag
+		}
+
+		resp, data, err := g.doAPI(ctx, url, accept, etag)
+		if err != nil {
+			return nil, err
+		}
+
+		limit, hasLimit := parseRateLimit(resp.Header)
+		if hasLimit {
+			g.observeRateLimit(ctx, limit)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			if cacheable {
+				g.cache.store(ctx, url, accept, resp.Header.Get("ETag"), data)
+			}
+
+			return data, nil
+		case resp.StatusCode == http.StatusNotModified && cached != nil:
+			slog.DebugContext(ctx, "Using cached response", "url", url)
+
+			return cached.Body, nil
+		case hasLimit && limit.remaining == 0 &&
+			(resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests):
+			err = g.waitForRateLimit(ctx, limit)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, parseError(resp, data)
+		}
+	}
+}
+
+func (g *ClientImpl) doAPI(ctx context.Context, url, accept, etag string) (*http.Response, []byte, error) {
+	req, err := g.newRequest(ctx, http.MethodGet, url, nil, true)
 	if err != nil {
-		return nil, fmt.Errorf("error creating GET request: %w", err)
+		return nil, nil, err
 	}
 
-	req.Header.Add("Accept", "application/vnd.github+json")
+	req.Header.Add("Accept", accept)
 	req.Header.Add("X-Github-Api-Version", "2022-11-28")
 
-	if token := os.Getenv("GH_TOKEN"); token != "" {
-		req.Header.Add("Authorization", "Bearer "+token)
+	if etag != "" {
+		req.Header.Add("If-None-Match", etag)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error executing request: %w", err)
-	}
-	defer resp.Body.Close()
+	return g.do(req)
+}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+func (g *ClientImpl) DownloadReleaseAsset(ctx context.Context, org, repo, release, asset string) ([]byte, error) {
+	return g.DownloadReleaseAssetByID(ctx, org, repo, release, asset, 0)
+}
+
+// DownloadReleaseAssetByID downloads an asset, looking up its ID when 0 and needed for the assets API.
+func (g *ClientImpl) DownloadReleaseAssetByID(
+	ctx context.Context, org, repo, release, asset string, id int64,
+) ([]byte, error) {
+	url := fmt.Sprintf("%s/%s/%s/releases/download/%s/%s",
+		g.downloadBaseURL, org, repo, release, asset)
+
+	// Downloads routed through a mirror bypass the assets API, whose URLs the mirror's rules may not cover
+	_, rewrite := rewriteURL(g.urlRewrites, url)
+
+	if g.assetsAPI && rewrite == nil && g.getToken() != "" {
+		return g.downloadReleaseAssetFromAPI(ctx, org, repo, release, asset, id)
 	}
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		return parseRelease(data)
-	default:
-		return nil, parseError(data)
+	slog.DebugContext(ctx, "Downloading release asset", "url", url)
+
+	return g.downloadArtifact(ctx, url)
+}
+
+// downloadReleaseAssetFromAPI downloads an asset by ID using the token, so that assets of private repositories
+// are accessible. The release download URLs used otherwise do not accept tokens.
+func (g *ClientImpl) downloadReleaseAssetFromAPI(
+	ctx context.Context, org, repo, release, asset string, id int64,
+) ([]byte, error) {
+	if id == 0 {
+		releaseInfo, err := g.GetReleaseByTag(ctx, org, repo, release)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching release %q: %w", release, err)
+		}
+
+		idx := slices.IndexFunc(releaseInfo.Assets, func(a Asset) bool { return a.Name == asset })
+		if idx == -1 {
+			return nil, &APIError{
+				StatusCode: http.StatusNotFound,
+				URL:        releaseInfo.URL,
+				Message:    fmt.Sprintf("asset %q not found in release %q", asset, release),
+			}
+		}
+
+		id = releaseInfo.Assets[idx].ID
 	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/assets/%d", g.baseURL, org, repo, id)
+
+	slog.DebugContext(ctx, "Downloading release asset through API", "url", url)
+
+	return g.getAPIWithAccept(ctx, url, octetStream)
 }
 
-func (g *ClientImpl) DownloadReleaseAsset(org, repo, release, asset string) ([]byte, error) {
-	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s",
-		org, repo, release, asset)
+func (g *ClientImpl) getToken() string {
+	g.tokenOnce.Do(func() {
+		g.token = g.credentials.ResolveToken()
+	})
 
-	ctx := context.Background()
-	slog.DebugContext(ctx, "Downloading asset from GitHub", "url", url)
+	return g.token
+}
+
+// newRequest creates a request for url, after applying any rewrite rule.
+// Rewritten requests carry the mirror's credentials in place of the token.
+func (g *ClientImpl) newRequest(
+	ctx context.Context, method, url string, body []byte, withToken bool,
+) (*http.Request, error) {
+	url, rewrite := rewriteURL(g.urlRewrites, url)
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
 
-	return downloadArtifact(url)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s request: %w", method, err)
+	}
+
+	switch {
+	case rewrite != nil:
+		slog.DebugContext(ctx, "Rewrote request URL", "url", url)
+
+		rewrite.authorize(req)
+	case withToken:
+		if token := g.getToken(); token != "" {
+			req.Header.Add("Authorization", g.authScheme+" "+token)
+		}
+	}
+
+	return req, nil
 }
 
-func downloadArtifact(url string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+func (g *ClientImpl) downloadArtifact(ctx context.Context, url string) ([]byte, error) {
+	withToken := g.downloadToken && sameHost(url, g.baseURL)
+
+	req, err := g.newRequest(ctx, http.MethodGet, url, nil, withToken)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	req.Header.Add("Accept", octetStream)
+
+	resp, data, err := g.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error requesting asset: %w", err)
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+	// Error pages must not be mistaken for the asset
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp, data)
 	}
 
 	return data, nil
 }
+
+// sameHost reports whether both URLs are served from the same host, so that tokens only reach the API's host.
+func sameHost(rawURL, otherURL string) bool {
+	parsed, err := neturl.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	other, err := neturl.Parse(otherURL)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Scheme == other.Scheme && parsed.Host == other.Host
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGetReleaseByTag_Success(t *testing.T) {
@@ -47,7 +50,7 @@ func TestGetReleaseByTag_Success(t *testing.T) {
 	// Create client pointing to mock server
 	client := NewClientWithBaseURL(server.URL)
 
-	result, err := client.GetReleaseByTag("owner", "repo", "v1.2.3")
+	result, err := client.GetReleaseByTag(t.Context(), "owner", "repo", "v1.2.3")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -82,7 +85,7 @@ func TestGetReleaseByTag_NotFound(t *testing.T) {
 	// Create client pointing to mock server
 	client := NewClientWithBaseURL(server.URL)
 
-	_, err := client.GetReleaseByTag("owner", "repo", "nonexistent")
+	_, err := client.GetReleaseByTag(t.Context(), "owner", "repo", "nonexistent")
 	if err == nil {
 		t.Fatal("Expected error, got nil")
 	}
@@ -126,7 +129,7 @@ func TestGetLatestRelease_Success(t *testing.T) {
 	// Create client pointing to mock server
 	client := NewClientWithBaseURL(server.URL)
 
-	result, err := client.GetLatestRelease("owner", "repo")
+	result, err := client.GetLatestRelease(t.Context(), "owner", "repo")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -161,7 +164,7 @@ func TestGetLatestRelease_NoReleases(t *testing.T) {
 	// Create client pointing to mock server
 	client := NewClientWithBaseURL(server.URL)
 
-	_, err := client.GetLatestRelease("owner", "repo")
+	_, err := client.GetLatestRelease(t.Context(), "owner", "repo")
 	if err == nil {
 		t.Fatal("Expected error, got nil")
 	}
@@ -170,3 +173,349 @@ func TestGetLatestRelease_NoReleases(t *testing.T) {
 		t.Errorf("Expected error message 'Not Found', got '%s'", err.Error())
 	}
 }
+
+func TestListReleases_Success(t *testing.T) {
+	publishedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	// Mock GitHub API response
+	mockReleases := []Release{
+		{
+			Name:        "v2.0.0",
+			TagName:     "v2.0.0",
+			Body:        "Breaking changes",
+			PublishedAt: publishedAt,
+		},
+		{
+			Name:       "v1.9.0-rc1",
+			TagName:    "v1.9.0-rc1",
+			Prerelease: true,
+		},
+	}
+
+	// Create mock server
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		// Verify request
+		if request.URL.Path != "/repos/owner/repo/releases" {
+			t.Errorf("Expected path '/repos/owner/repo/releases', got '%s'", request.URL.Path)
+		}
+
+		if request.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Expected per_page '100', got '%s'", request.URL.Query().Get("per_page"))
+		}
+
+		// Return mock response
+		responseWriter.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(responseWriter).Encode(mockReleases)
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.ListReleases(t.Context(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(result))
+	}
+
+	if result[0].Body != "Breaking changes" {
+		t.Errorf("Expected body 'Breaking changes', got '%s'", result[0].Body)
+	}
+
+	if !result[0].PublishedAt.Equal(publishedAt) {
+		t.Errorf("Expected published at %v, got %v", publishedAt, result[0].PublishedAt)
+	}
+
+	if !result[1].Prerelease {
+		t.Error("Expected second release to be a prerelease")
+	}
+}
+
+func TestDownloadReleaseAsset_WithTokenUsesAssetsAPI(t *testing.T) {
+	t.Setenv("GH_TOKEN", "secret")
+
+	mockRelease := Release{
+		TagName: "v1.2.3",
+		Assets: []Asset{
+			{ID: 41, Name: "app-darwin-amd64.tar.gz"},
+			{ID: 42, Name: "app-linux-amd64.tar.gz"},
+		},
+	}
+
+	// Create mock server
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected Authorization header 'Bearer secret', got '%s'", request.Header.Get("Authorization"))
+		}
+
+		switch request.URL.Path {
+		case "/repos/owner/repo/releases/tags/v1.2.3":
+			responseWriter.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(responseWriter).Encode(mockRelease)
+		case "/repos/owner/repo/releases/assets/42":
+			if request.Header.Get("Accept") != "application/octet-stream" {
+				t.Errorf("Expected Accept header 'application/octet-stream', got '%s'", request.Header.Get("Accept"))
+			}
+
+			_, _ = responseWriter.Write([]byte("private asset"))
+		default:
+			t.Errorf("Unexpected request path '%s'", request.URL.Path)
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app-linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "private asset" {
+		t.Errorf("Expected asset contents 'private asset', got '%s'", string(result))
+	}
+
+	_, err = client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app-windows-amd64.zip")
+	if err == nil || err.Error() != `asset "app-windows-amd64.zip" not found in release "v1.2.3"` {
+		t.Errorf("Expected missing asset error, got %v", err)
+	}
+}
+
+func TestDownloadReleaseAssetByID_SkipsReleaseLookup(t *testing.T) {
+	t.Setenv("GH_TOKEN", "secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/repos/owner/repo/releases/assets/42" {
+			t.Errorf("Unexpected request path '%s'", request.URL.Path)
+			responseWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = responseWriter.Write([]byte("private asset"))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.DownloadReleaseAssetByID(t.Context(), "owner", "repo", "v1.2.3", "app-linux-amd64.tar.gz", 42)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "private asset" {
+		t.Errorf("Expected asset contents 'private asset', got '%s'", string(result))
+	}
+}
+
+func TestDownloadReleaseAsset_WithoutTokenUsesDownloadURL(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	// Create mock server
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/owner/repo/releases/download/v1.2.3/app-linux-amd64.tar.gz" {
+			t.Errorf("Unexpected request path '%s'", request.URL.Path)
+		}
+
+		_, _ = responseWriter.Write([]byte("public asset"))
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithConfig(ClientConfig{
+		BaseURL:         server.URL,
+		DownloadBaseURL: server.URL,
+		Credentials:     Credentials{EnvVars: []string{"GH_TOKEN"}},
+		AuthScheme:      "Bearer",
+		AssetsAPI:       true,
+	})
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app-linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "public asset" {
+		t.Errorf("Expected asset contents 'public asset', got '%s'", string(result))
+	}
+}
+
+func TestDownloadReleaseAsset_DownloadTokenOnlySentToAPIHost(t *testing.T) {
+	t.Setenv("GH_TOKEN", "secret")
+
+	// Create mock download server on a different host than the API
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "" {
+			t.Errorf("Expected no Authorization header, got '%s'", request.Header.Get("Authorization"))
+		}
+
+		_, _ = responseWriter.Write([]byte("public asset"))
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithConfig(ClientConfig{
+		BaseURL:         "https://api.example.com",
+		DownloadBaseURL: server.URL,
+		Credentials:     Credentials{EnvVars: []string{"GH_TOKEN"}},
+		AuthScheme:      "token",
+		DownloadToken:   true,
+	})
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app-linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "public asset" {
+		t.Errorf("Expected asset contents 'public asset', got '%s'", string(result))
+	}
+}
+
+func TestDownloadReleaseAsset_RewrittenToMirror(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	netrcPath := filepath.Join(t.TempDir(), "netrc")
+
+	err := os.WriteFile(netrcPath, []byte("machine 127.0.0.1\n  login builder\n  password hunter2\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NETRC", netrcPath)
+
+	// Create mock mirror
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/github/owner/repo/releases/download/v1.2.3/app-linux-amd64.tar.gz" {
+			t.Errorf("Unexpected request path '%s'", request.URL.Path)
+		}
+
+		login, password, ok := request.BasicAuth()
+		if !ok || login != "builder" || password != "hunter2" {
+			t.Errorf("Expected netrc credentials, got '%s' '%s'", login, password)
+		}
+
+		_, _ = responseWriter.Write([]byte("mirrored asset"))
+	}))
+	defer server.Close()
+
+	config := DefaultClientConfig()
+	config.URLRewrites = []URLRewrite{
+		{From: "https://github.com/", To: server.URL + "/github/"},
+	}
+
+	client := NewClientWithConfig(config)
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app-linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "mirrored asset" {
+		t.Errorf("Expected asset contents 'mirrored asset', got '%s'", string(result))
+	}
+}
+
+func TestDownloadReleaseAsset_RewrittenToMirrorWithToken(t *testing.T) {
+	t.Setenv("GH_TOKEN", "github-token")
+	t.Setenv("MIRROR_TOKEN", "mirror-token")
+
+	// Create mock mirror, which only mirrors release downloads
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/github/owner/repo/releases/download/v1.2.3/app-linux-amd64.tar.gz" {
+			t.Errorf("Unexpected request path '%s'", request.URL.Path)
+		}
+
+		if request.Header.Get("Authorization") != "Bearer mirror-token" {
+			t.Errorf("Expected Authorization header 'Bearer mirror-token', got '%s'", request.Header.Get("Authorization"))
+		}
+
+		_, _ = responseWriter.Write([]byte("mirrored asset"))
+	}))
+	defer server.Close()
+
+	config := DefaultClientConfig()
+	config.URLRewrites = []URLRewrite{
+		{From: "https://github.com/", To: server.URL + "/github/", TokenEnvVar: "MIRROR_TOKEN"},
+	}
+
+	client := NewClientWithConfig(config)
+
+	// The token would otherwise route the download through the assets API on api.github.com
+	result, err := client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app-linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "mirrored asset" {
+		t.Errorf("Expected asset contents 'mirrored asset', got '%s'", string(result))
+	}
+}
+
+func TestGetLatestRelease_RewrittenToMirror(t *testing.T) {
+	t.Setenv("GH_TOKEN", "github-token")
+	t.Setenv("MIRROR_TOKEN", "mirror-token")
+
+	// Create mock mirror
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/api/github/repos/owner/repo/releases/latest" {
+			t.Errorf("Unexpected request path '%s'", request.URL.Path)
+		}
+
+		// The GitHub token is not sent to the mirror
+		if request.Header.Get("Authorization") != "Bearer mirror-token" {
+			t.Errorf("Expected Authorization header 'Bearer mirror-token', got '%s'", request.Header.Get("Authorization"))
+		}
+
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.2.3"})
+	}))
+	defer server.Close()
+
+	config := DefaultClientConfig()
+	config.URLRewrites = []URLRewrite{
+		{From: "https://api.github.com/", To: server.URL + "/api/github/", TokenEnvVar: "MIRROR_TOKEN"},
+	}
+
+	client := NewClientWithConfig(config)
+
+	result, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Name != "v1.2.3" {
+		t.Errorf("Expected release name 'v1.2.3', got '%s'", result.Name)
+	}
+}
+
+func TestParseNetrc(t *testing.T) {
+	content := "machine example.com login alice password one\n" +
+		"machine artifactory.corp\n  login bob\n  password two\n" +
+		"default login anonymous password guest\n"
+
+	tests := []struct {
+		host     string
+		login    string
+		password string
+	}{
+		{"artifactory.corp", "bob", "two"},
+		{"example.com", "alice", "one"},
+		{"unknown.corp", "anonymous", "guest"},
+	}
+
+	for _, tt := range tests {
+		login, password, ok := parseNetrc(content, tt.host, true)
+		if !ok || login != tt.login || password != tt.password {
+			t.Errorf("parseNetrc(%q) = %q, %q, %v, want %q, %q", tt.host, login, password, ok, tt.login, tt.password)
+		}
+	}
+
+	if _, _, ok := parseNetrc(content, "unknown.corp", false); ok {
+		t.Error("parseNetrc() expected no credentials when the default entry is not allowed")
+	}
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Credentials describes where the access token for a GitHub instance may be found.
+//
+// Sources are checked in order:
+//  1. Token, such as configured in grab settings
+//  2. each of EnvVars
+//  3. the GitHub CLI's hosts.yml, for Host
+//  4. the password of the netrc entry for Host
+type Credentials struct {
+	// Host is the web host of the instance (e.g. github.com), used to find gh CLI and netrc entries
+	Host string
+
+	// Token is used when set, ahead of any other source
+	Token string
+
+	// EnvVars name environment variables that may hold the token, in order of preference
+	EnvVars []string
+}
+
+type ghHostConfig struct {
+	OAuthToken string `yaml:"oauth_token"` //nolint:tagliatelle
+}
+
+// ResolveToken returns the first token found, or an empty string when there is none.
+// The source of the token is logged with the token redacted.
+func (c *Credentials) ResolveToken() string {
+	token, source := c.resolve()
+
+	ctx := context.Background()
+	if token == "" {
+		slog.DebugContext(ctx, "No access token found", "host", c.Host)
+	} else {
+		slog.DebugContext(ctx, "Using access token", "host", c.Host, "source", source, "token", redactToken(token))
+	}
+
+	return token
+}
+
+func (c *Credentials) resolve() (string, string) {
+	if c.Token != "" {
+		return c.Token, "settings"
+	}
+
+	for _, envVar := range c.EnvVars {
+		if token := os.Getenv(envVar); token != "" {
+			return token, "environment variable " + envVar
+		}
+	}
+
+	if c.Host == "" {
+		return "", ""
+	}
+
+	if token := ghCLIToken(c.Host); token != "" {
+		return token, "gh CLI hosts.yml"
+	}
+
+	if _, password, ok := netrcCredentials(c.Host, false); ok && password != "" {
+		return password, "netrc"
+	}
+
+	return "", ""
+}
+
+// ghCLIToken reads the token stored for host by `gh auth login`.
+// Recent versions of the GitHub CLI keep tokens in the system keyring instead, which is not consulted.
+func ghCLIToken(host string) string {
+	configDir := os.Getenv("GH_CONFIG_DIR")
+	if configDir == "" {
+		if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
+			configDir = filepath.Join(xdgConfig, "gh")
+		} else if homeDir, err := os.UserHomeDir(); err == nil {
+			configDir = filepath.Join(homeDir, ".config", "gh")
+		}
+	}
+
+	if configDir == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+
+	var hosts map[string]ghHostConfig
+
+	err = yaml.Unmarshal(data, &hosts)
+	if err != nil {
+		ctx := context.Background()
+		slog.DebugContext(ctx, "Ignoring unreadable gh CLI hosts.yml", "error", err)
+
+		return ""
+	}
+
+	return hosts[host].OAuthToken
+}
+
+// redactToken keeps only the prefix of a token, which identifies its type (e.g. ghp_).
+func redactToken(token string) string {
+	const visible = 4
+
+	if len(token) <= visible*2 {
+		return "****"
+	}
+
+	return token[:visible] + "****"
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// isolateCredentials points credential files at an empty directory and clears token environment variables.
+func isolateCredentials(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	t.Setenv("GH_CONFIG_DIR", dir)
+	t.Setenv("NETRC", filepath.Join(dir, "netrc"))
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveToken_Order(t *testing.T) {
+	dir := isolateCredentials(t)
+
+	credentials := Credentials{
+		Host:    "github.com",
+		EnvVars: []string{"GH_TOKEN", "GITHUB_TOKEN"},
+	}
+
+	if token := credentials.ResolveToken(); token != "" {
+		t.Errorf("Expected no token, got '%s'", token)
+	}
+
+	writeFile(t, filepath.Join(dir, "netrc"), "machine github.com login user password from-netrc\n")
+
+	if token := credentials.ResolveToken(); token != "from-netrc" {
+		t.Errorf("Expected token 'from-netrc', got '%s'", token)
+	}
+
+	writeFile(t, filepath.Join(dir, "hosts.yml"), "github.com:\n    oauth_token: from-gh\n    user: someone\n")
+
+	if token := credentials.ResolveToken(); token != "from-gh" {
+		t.Errorf("Expected token 'from-gh', got '%s'", token)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "from-github-token")
+
+	if token := credentials.ResolveToken(); token != "from-github-token" {
+		t.Errorf("Expected token 'from-github-token', got '%s'", token)
+	}
+
+	t.Setenv("GH_TOKEN", "from-gh-token")
+
+	if token := credentials.ResolveToken(); token != "from-gh-token" {
+		t.Errorf("Expected token 'from-gh-token', got '%s'", token)
+	}
+
+	credentials.Token = "from-settings"
+
+	if token := credentials.ResolveToken(); token != "from-settings" {
+		t.Errorf("Expected token 'from-settings', got '%s'", token)
+	}
+}
+
+func TestResolveToken_OtherHosts(t *testing.T) {
+	dir := isolateCredentials(t)
+
+	writeFile(t, filepath.Join(dir, "netrc"), "default login user password from-default\n")
+	writeFile(t, filepath.Join(dir, "hosts.yml"), "ghe.corp:\n    oauth_token: from-gh\n")
+
+	credentials := Credentials{Host: "github.com"}
+
+	// Neither another host's gh CLI token nor the netrc default entry is used
+	if token := credentials.ResolveToken(); token != "" {
+		t.Errorf("Expected no token, got '%s'", token)
+	}
+}
+
+func TestRedactToken(t *testing.T) {
+	if redacted := redactToken("ghp_abcdefghijklmnop"); redacted != "ghp_****" {
+		t.Errorf("Expected 'ghp_****', got '%s'", redacted)
+	}
+
+	if redacted := redactToken("short"); redacted != "****" {
+		t.Errorf("Expected '****', got '%s'", redacted)
+	}
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors matched with errors.Is, classifying unsuccessful responses.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// APIError reports an unsuccessful response from the API or a download.
+type APIError struct {
+	StatusCode int
+	URL        string
+
+	// RequestID identifies the request to GitHub support, when the server provides one
+	RequestID string
+
+	// Message is the server's explanation, or the status text when there is none
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s (request ID %s)", e.Message, e.RequestID)
+}
+
+// Unwrap classifies the error by its status code.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(e.Message), "rate limit"):
+		// Secondary rate limits are reported as forbidden
+		return ErrRateLimited
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// Is matches an exhausted rate limit as ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+type errorBody struct {
+	Message string `json:"message"`
+}
+
+// parseError describes an unsuccessful response, using the message of a JSON error body when there is one.
+func parseError(resp *http.Response, data []byte) error {
+	var body errorBody
+
+	message := ""
+	if json.Unmarshal(data, &body) == nil {
+		message = body.Message
+	}
+
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	if message == "" {
+		message = resp.Status
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		URL:        resp.Request.URL.Redacted(),
+		RequestID:  resp.Header.Get("X-Github-Request-Id"),
+		Message:    message,
+	}
+}
@@ -0,0 +1,87 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIError_Classification(t *testing.T) {
+	cases := []struct {
+		status   int
+		message  string
+		expected error
+	}{
+		{http.StatusNotFound, "Not Found", ErrNotFound},
+		{http.StatusUnauthorized, "Bad credentials", ErrUnauthorized},
+		{http.StatusForbidden, "Resource not accessible by integration", ErrUnauthorized},
+		{http.StatusForbidden, "You have exceeded a secondary rate limit", ErrRateLimited},
+		{http.StatusTooManyRequests, "Too Many Requests", ErrRateLimited},
+		{http.StatusBadGateway, "Bad Gateway", ErrServer},
+		{http.StatusUnprocessableEntity, "Validation Failed", nil},
+	}
+
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrServer}
+
+	for _, tc := range cases {
+		err := &APIError{StatusCode: tc.status, Message: tc.message}
+
+		for _, sentinel := range sentinels {
+			if errors.Is(err, sentinel) != (sentinel == tc.expected) {
+				t.Errorf("Expected %d %q to match %v only, but errors.Is(%v) = %t",
+					tc.status, tc.message, tc.expected, sentinel, errors.Is(err, sentinel))
+			}
+		}
+	}
+}
+
+func TestRateLimitError_IsRateLimited(t *testing.T) {
+	var err error = &RateLimitError{Limit: 60, Reset: time.Now()}
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("Expected an exhausted rate limit to match ErrRateLimited")
+	}
+}
+
+func TestDownloadReleaseAsset_NotFoundPage(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.Header().Set("Content-Type", "text/html")
+		responseWriter.Header().Set("X-GitHub-Request-Id", "ABCD:1234")
+		responseWriter.WriteHeader(http.StatusNotFound)
+		_, _ = responseWriter.Write([]byte("<html>Page not found</html>"))
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(ClientConfig{
+		BaseURL:         server.URL,
+		DownloadBaseURL: server.URL,
+		Credentials:     Credentials{EnvVars: []string{"GH_TOKEN"}},
+	})
+
+	data, err := client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app-linux-amd64.tar.gz")
+	if data != nil {
+		t.Errorf("Expected no data for an error page, got '%s'", string(data))
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %T", err)
+	}
+
+	expectedURL := server.URL + "/owner/repo/releases/download/v1.2.3/app-linux-amd64.tar.gz"
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.URL != expectedURL || apiErr.RequestID != "ABCD:1234" {
+		t.Errorf("Expected the status, URL and request ID of the response, got %+v", apiErr)
+	}
+
+	if err.Error() != "Not Found (request ID ABCD:1234)" {
+		t.Errorf("Expected error message 'Not Found (request ID ABCD:1234)', got '%s'", err.Error())
+	}
+}
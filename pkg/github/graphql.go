@@ -0,0 +1,177 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// graphQLBatchSize is the number of repositories looked up by each GraphQL query.
+const graphQLBatchSize = 50
+
+// ErrBatchUnavailable reports that batch lookups are not possible, as the GraphQL API requires a token.
+var ErrBatchUnavailable = errors.New("batch lookups require an access token and a GraphQL API")
+
+// Repository identifies a repository by its owner and name.
+type Repository struct {
+	Owner string
+	Name  string
+}
+
+// BatchClient looks up the latest releases of many repositories with few requests.
+type BatchClient interface {
+	// GetLatestReleases returns the latest release of each repository that has one.
+	// Repositories that could not be looked up are missing from the result.
+	GetLatestReleases(ctx context.Context, repos []Repository) (map[Repository]*Release, error)
+}
+
+// GraphQLURL returns the GraphQL endpoint alongside a REST API root,
+// such as https://api.github.com/graphql or https://ghe.example.com/api/graphql.
+func GraphQLURL(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/v3") + "/graphql"
+}
+
+type graphQLRequest struct {
+	Query     string            `json:"query"`
+	Variables map[string]string `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]*graphQLRepository `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type graphQLRepository struct {
+	LatestRelease *struct {
+		Name         string    `json:"name"`
+		TagName      string    `json:"tagName"`
+		URL          string    `json:"url"`
+		Description  string    `json:"description"`
+		PublishedAt  time.Time `json:"publishedAt"`
+		IsPrerelease bool      `json:"isPrerelease"`
+	} `json:"latestRelease"`
+}
+
+// GetLatestReleases looks up latest releases through the GraphQL API, using one aliased query per batch.
+func (g *ClientImpl) GetLatestReleases(ctx context.Context, repos []Repository) (map[Repository]*Release, error) {
+	if g.graphQLURL == "" || g.getToken() == "" {
+		return nil, ErrBatchUnavailable
+	}
+
+	output := make(map[Repository]*Release, len(repos))
+
+	for start := 0; start < len(repos); start += graphQLBatchSize {
+		batch := repos[start:min(start+graphQLBatchSize, len(repos))]
+
+		err := g.queryLatestReleases(ctx, batch, output)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
+
+func (g *ClientImpl) queryLatestReleases(
+	ctx context.Context, repos []Repository, output map[Repository]*Release,
+) error {
+	slog.DebugContext(ctx, "Querying latest releases", "url", g.graphQLURL, "repositories", len(repos))
+
+	data, err := g.postGraphQL(ctx, buildLatestReleasesQuery(repos))
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return fmt.Errorf("error parsing response as JSON: %w", err)
+	}
+
+	if response.Data == nil && len(response.Errors) > 0 {
+		return fmt.Errorf("error querying latest releases: %s", response.Errors[0].Message)
+	}
+
+	// Missing repositories are reported as errors alongside the data of the others
+	for _, queryErr := range response.Errors {
+		slog.DebugContext(ctx, "Latest release query reported an error", "error", queryErr.Message)
+	}
+
+	for idx, repo := range repos {
+		result := response.Data[fmt.Sprintf("r%d", idx)]
+		if result == nil || result.LatestRelease == nil {
+			continue
+		}
+
+		output[repo] = &Release{
+			Name:        result.LatestRelease.Name,
+			URL:         result.LatestRelease.URL,
+			TagName:     result.LatestRelease.TagName,
+			Body:        result.LatestRelease.Description,
+			PublishedAt: result.LatestRelease.PublishedAt,
+			Prerelease:  result.LatestRelease.IsPrerelease,
+		}
+	}
+
+	return nil
+}
+
+// buildLatestReleasesQuery aliases a repository lookup per repository, passing names as variables.
+func buildLatestReleasesQuery(repos []Repository) graphQLRequest {
+	var parameters, fields strings.Builder
+
+	variables := make(map[string]string, 2*len(repos)) //nolint:mnd
+
+	for idx, repo := range repos {
+		if idx > 0 {
+			parameters.WriteString(", ")
+		}
+
+		fmt.Fprintf(&parameters, "$o%d: String!, $n%d: String!", idx, idx)
+		fmt.Fprintf(&fields,
+			"r%d: repository(owner: $o%d, name: $n%d) "+
+				"{ latestRelease { name tagName url description publishedAt isPrerelease } }\n",
+			idx, idx, idx)
+
+		variables[fmt.Sprintf("o%d", idx)] = repo.Owner
+		variables[fmt.Sprintf("n%d", idx)] = repo.Name
+	}
+
+	return graphQLRequest{
+		Query:     fmt.Sprintf("query(%s) {\n%s}", parameters.String(), fields.String()),
+		Variables: variables,
+	}
+}
+
+func (g *ClientImpl) postGraphQL(ctx context.Context, query graphQLRequest) ([]byte, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	req, err := g.newRequest(ctx, http.MethodPost, g.graphQLURL, body, true)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, data, err := g.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp, data)
+	}
+
+	return data, nil
+}
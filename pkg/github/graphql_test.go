@@ -0,0 +1,87 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetLatestReleases(t *testing.T) {
+	t.Setenv("GH_TOKEN", "test-token")
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost || request.URL.Path != "/graphql" {
+			t.Errorf("Expected POST /graphql, got %s %s", request.Method, request.URL.Path)
+		}
+
+		if request.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Expected bearer token, got '%s'", request.Header.Get("Authorization"))
+		}
+
+		var query graphQLRequest
+
+		err := json.NewDecoder(request.Body).Decode(&query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if query.Variables["o0"] != "junegunn" || query.Variables["n0"] != "fzf" ||
+			query.Variables["o1"] != "owner" || query.Variables["n1"] != "missing" {
+			t.Errorf("Unexpected variables %v", query.Variables)
+		}
+
+		_, _ = responseWriter.Write([]byte(`{
+			"data": {
+				"r0": {"latestRelease": {"name": "v0.60.0", "tagName": "v0.60.0", "url": "https://github.com/junegunn/fzf/releases/tag/v0.60.0", "publishedAt": "2025-02-16T04:00:00Z"}},
+				"r1": null
+			},
+			"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository with the name 'owner/missing'."}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+
+	fzf := Repository{Owner: "junegunn", Name: "fzf"}
+	missing := Repository{Owner: "owner", Name: "missing"}
+
+	result, err := client.GetLatestReleases(t.Context(), []Repository{fzf, missing})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 release, got %d", len(result))
+	}
+
+	if result[fzf].Name != "v0.60.0" || result[fzf].TagName != "v0.60.0" || result[fzf].PublishedAt.IsZero() {
+		t.Errorf("Unexpected release %+v", result[fzf])
+	}
+}
+
+func TestGetLatestReleases_WithoutToken(t *testing.T) {
+	isolateCredentials(t)
+
+	client := NewClientWithBaseURL("http://unused.invalid")
+
+	_, err := client.GetLatestReleases(t.Context(), []Repository{{Owner: "junegunn", Name: "fzf"}})
+	if !errors.Is(err, ErrBatchUnavailable) {
+		t.Errorf("Expected ErrBatchUnavailable, got %v", err)
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	cases := map[string]string{
+		"https://api.github.com":          "https://api.github.com/graphql",
+		"https://ghe.example.com/api/v3":  "https://ghe.example.com/api/graphql",
+		"https://ghe.example.com/api/v3/": "https://ghe.example.com/api/graphql",
+	}
+
+	for baseURL, expected := range cases {
+		if actual := GraphQLURL(baseURL); actual != expected {
+			t.Errorf("Expected '%s' for '%s', got '%s'", expected, baseURL, actual)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// netrcCredentials returns the login and password for host from the netrc file at $NETRC or ~/.netrc.
+// The default entry is used for other hosts only when allowDefault is set.
+func netrcCredentials(host string, allowDefault bool) (string, string, bool) {
+	path := os.Getenv("NETRC")
+	if path == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", false
+		}
+
+		path = filepath.Join(homeDir, ".netrc")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+
+	return parseNetrc(string(data), host, allowDefault)
+}
+
+// parseNetrc finds the credentials for host in netrc content, optionally falling back to the default entry.
+func parseNetrc(content, host string, allowDefault bool) (string, string, bool) {
+	type entry struct {
+		login    string
+		password string
+	}
+
+	var (
+		current  *entry
+		matched  *entry
+		fallback *entry
+	)
+
+	fields := strings.Fields(content)
+
+	for idx := 0; idx < len(fields); idx++ {
+		switch fields[idx] {
+		case "machine":
+			current = &entry{}
+
+			if idx+1 < len(fields) && fields[idx+1] == host && matched == nil {
+				matched = current
+			}
+
+			idx++
+		case "default":
+			current = &entry{}
+
+			if fallback == nil {
+				fallback = current
+			}
+		case "login":
+			if current != nil && idx+1 < len(fields) {
+				current.login = fields[idx+1]
+			}
+
+			idx++
+		case "password":
+			if current != nil && idx+1 < len(fields) {
+				current.password = fields[idx+1]
+			}
+
+			idx++
+		}
+	}
+
+	if matched == nil && allowDefault {
+		matched = fallback
+	}
+
+	if matched == nil {
+		return "", "", false
+	}
+
+	return matched.login, matched.password, true
+}
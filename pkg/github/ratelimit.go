@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// rateLimitWarningThreshold is the number of remaining requests at which a warning is logged.
+const rateLimitWarningThreshold = 10
+
+// RateLimitError reports that the API rate limit is exhausted.
+type RateLimitError struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf(
+		"GitHub API rate limit exceeded: %d of %d requests remain until the limit resets at %s (in %s); "+
+			"use an access token or pass --wait-for-rate-limit",
+		e.Remaining, e.Limit, e.Reset.Local().Format(time.Kitchen), time.Until(e.Reset).Round(time.Second))
+}
+
+type rateLimit struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// parseRateLimit reads the rate limit headers of an API response.
+func parseRateLimit(header http.Header) (*rateLimit, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return nil, false
+	}
+
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	// The limit is informational, so a missing header is tolerated
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+
+	return &rateLimit{
+		limit:     limit,
+		remaining: remaining,
+		reset:     time.Unix(reset, 0),
+	}, true
+}
+
+// observeRateLimit warns, once, when few requests remain.
+func (g *ClientImpl) observeRateLimit(ctx context.Context, limit *rateLimit) {
+	if limit.remaining == 0 || limit.remaining > rateLimitWarningThreshold {
+		return
+	}
+
+	g.rateLimitWarning.Do(func() {
+		slog.WarnContext(ctx, "GitHub API rate limit nearly exhausted",
+			"remaining", limit.remaining, "limit", limit.limit, "reset", limit.reset.Local().Format(time.Kitchen))
+	})
+}
+
+// waitForRateLimit sleeps until the rate limit resets, or fails when waiting is not enabled.
+func (g *ClientImpl) waitForRateLimit(ctx context.Context, limit *rateLimit) error {
+	if !g.waitForRateLimitReset {
+		return &RateLimitError{
+			Limit:     limit.limit,
+			Remaining: limit.remaining,
+			Reset:     limit.reset,
+		}
+	}
+
+	// Allow for clock skew between the API and this host
+	wait := max(limit.reset.Sub(g.now()), 0) + time.Second
+
+	slog.WarnContext(ctx, "GitHub API rate limit exceeded, waiting for it to reset", "wait", wait.Round(time.Second))
+
+	return g.sleep(ctx, wait)
+}
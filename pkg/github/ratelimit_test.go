@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rateLimitedServer rejects the first request as rate limited, and serves a release afterwards.
+func rateLimitedServer(t *testing.T, reset time.Time) (*httptest.Server, *int) {
+	t.Helper()
+
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		responseWriter.Header().Set("X-RateLimit-Limit", "60")
+		responseWriter.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if requests == 1 {
+			responseWriter.Header().Set("X-RateLimit-Remaining", "0")
+			responseWriter.WriteHeader(http.StatusForbidden)
+			_, _ = responseWriter.Write([]byte(`{"message": "API rate limit exceeded for 127.0.0.1."}`))
+
+			return
+		}
+
+		responseWriter.Header().Set("X-RateLimit-Remaining", "59")
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.2.3"})
+	}))
+
+	return server, &requests
+}
+
+func TestGetLatestRelease_RateLimitExceeded(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	reset := time.Now().Add(30 * time.Minute)
+
+	server, requests := rateLimitedServer(t, reset)
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+
+	_, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected a RateLimitError, got %v", err)
+	}
+
+	if rateLimitErr.Remaining != 0 || rateLimitErr.Limit != 60 || rateLimitErr.Reset.Unix() != reset.Unix() {
+		t.Errorf("Unexpected rate limit details %+v", rateLimitErr)
+	}
+
+	if !strings.Contains(err.Error(), "0 of 60 requests remain until the limit resets at") {
+		t.Errorf("Expected message to describe the limit, got '%s'", err.Error())
+	}
+
+	if *requests != 1 {
+		t.Errorf("Expected 1 request, got %d", *requests)
+	}
+}
+
+func TestGetLatestRelease_WaitForRateLimit(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	now := time.Now()
+	reset := now.Add(10 * time.Minute)
+
+	server, requests := rateLimitedServer(t, reset)
+	defer server.Close()
+
+	config := DefaultClientConfig()
+	config.BaseURL = server.URL
+	config.WaitForRateLimit = true
+
+	client := NewClientWithConfig(config)
+	client.now = func() time.Time { return now }
+
+	var slept time.Duration
+
+	client.sleep = func(_ context.Context, duration time.Duration) error {
+		slept += duration
+
+		return nil
+	}
+
+	result, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Name != "v1.2.3" {
+		t.Errorf("Expected release name 'v1.2.3', got '%s'", result.Name)
+	}
+
+	if *requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", *requests)
+	}
+
+	// Sleeps until the reset, rounded to whole seconds, plus a second of allowance
+	if slept < 10*time.Minute || slept > 10*time.Minute+2*time.Second {
+		t.Errorf("Expected to sleep for about 10m, slept %s", slept)
+	}
+}
@@ -1,15 +1,21 @@
 package github
 
+import "time"
+
 // Release represents a GitHub release with asset information.
 type Release struct {
-	Name    string  `json:"name"`
-	URL     string  `json:"html_url"` //nolint:tagliatelle
-	TagName string  `json:"tag_name"` //nolint:tagliatelle
-	Assets  []Asset `json:"assets"`
+	Name        string    `json:"name"`
+	URL         string    `json:"html_url"` //nolint:tagliatelle
+	TagName     string    `json:"tag_name"` //nolint:tagliatelle
+	Body        string    `json:"body"`
+	PublishedAt time.Time `json:"published_at"` //nolint:tagliatelle
+	Prerelease  bool      `json:"prerelease"`
+	Assets      []Asset   `json:"assets"`
 }
 
 // Asset represents a GitHub release asset.
 type Asset struct {
+	ID          int64  `json:"id"`
 	Name        string `json:"name"`
 	Size        int64  `json:"size"`
 	DownloadURL string `json:"browser_download_url"` //nolint:tagliatelle
@@ -0,0 +1,207 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/noizwaves/grab/pkg/progress"
+)
+
+// RetryConfig controls how requests failing with network errors or transient server errors are retried.
+type RetryConfig struct {
+	// MaxAttempts is the number of attempts made for a request, including the first
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry, doubling for each retry after it
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts, unless the server asks for longer with Retry-After
+	MaxBackoff time.Duration
+
+	// AttemptTimeout bounds the wait for the response headers of each attempt (0 for no limit).
+	// Reading the body is left to the transport's read timeouts, so that large downloads are not cut short.
+	AttemptTimeout time.Duration
+}
+
+// ErrAttemptTimeout reports an attempt whose response headers did not arrive within the AttemptTimeout.
+var ErrAttemptTimeout = errors.New("no response received in time")
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// do performs a request and reads the response body.
+// Network errors and transient server errors of GET requests are retried with jittered exponential backoff,
+// whereas other methods are attempted once, as they may not be safe to repeat.
+func (g *ClientImpl) do(req *http.Request) (*http.Response, []byte, error) {
+	ctx := req.Context()
+
+	attempts := max(g.retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		slog.DebugContext(ctx, "Sending request", "url", req.URL.Redacted(), "attempt", attempt)
+
+		resp, data, err := g.attempt(req)
+		if attempt >= attempts || req.Method != http.MethodGet || !shouldRetry(resp, err) {
+			return resp, data, err
+		}
+
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+		}
+
+		delay := g.backoff(attempt, resp)
+
+		slog.DebugContext(ctx, "Request failed, retrying",
+			"url", req.URL.Redacted(), "attempt", attempt, "reason", reason, "delay", delay)
+
+		err = g.sleep(ctx, delay)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func (g *ClientImpl) attempt(req *http.Request) (*http.Response, []byte, error) {
+	resp, release, err := g.retry.Send(g.httpClient, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer release()
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+
+	// Downloads report their progress, whereas metadata requests are too small to
+	if req.Header.Get("Accept") == octetStream && resp.StatusCode == http.StatusOK {
+		body = progress.NewReader(req.Context(), resp.Body, resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return resp, data, nil
+}
+
+// Send sends req with client, giving up when the response headers do not arrive within the AttemptTimeout.
+// The returned release function must be called once the response body has been read.
+func (c RetryConfig) Send(client *http.Client, req *http.Request) (*http.Response, func(), error) {
+	if c.AttemptTimeout <= 0 {
+		resp, err := client.Do(req)
+
+		return resp, func() {}, err //nolint:wrapcheck
+	}
+
+	ctx, cancel := context.WithCancelCause(req.Context())
+	timer := time.AfterFunc(c.AttemptTimeout, func() {
+		cancel(fmt.Errorf("%w after %s", ErrAttemptTimeout, c.AttemptTimeout))
+	})
+
+	resp, err := client.Do(req.WithContext(ctx))
+
+	timer.Stop()
+
+	if err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, ErrAttemptTimeout) {
+			err = cause
+		}
+
+		cancel(nil)
+
+		return nil, func() {}, err //nolint:wrapcheck
+	}
+
+	return resp, func() { cancel(nil) }, nil
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		// Secondary rate limits ask for a delay, whereas an exhausted primary limit is handled by the caller
+		return resp.Header.Get("Retry-After") != "" && resp.Header.Get("X-RateLimit-Remaining") != "0"
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before retrying, preferring the server's Retry-After header.
+func (g *ClientImpl) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), g.now()); ok {
+			return delay
+		}
+	}
+
+	return g.retry.Backoff(attempt)
+}
+
+// Backoff returns the delay before retrying after a failed attempt, doubling from InitialBackoff up to MaxBackoff.
+func (c RetryConfig) Backoff(attempt int) time.Duration {
+	// Comparing before shifting keeps the doubling from overflowing
+	delay := c.MaxBackoff
+	if shift := attempt - 1; c.InitialBackoff <= c.MaxBackoff>>shift {
+		delay = c.InitialBackoff << shift
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter keeps at least half the delay, while spreading out clients that failed together
+	half := delay / 2
+
+	return half + time.Duration(rand.Int64N(int64(half)+1)) //nolint:gosec
+}
+
+// sleepContext waits for duration, returning early with an error if ctx is cancelled.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting to retry: %w", ctx.Err())
+	}
+}
+
+// parseRetryAfter reads a Retry-After header, given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,314 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, and serves a release afterwards.
+func flakyServer(t *testing.T, failures int, status int, header http.Header) (*httptest.Server, *int) {
+	t.Helper()
+
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		if requests <= failures {
+			for key, values := range header {
+				responseWriter.Header()[key] = values
+			}
+
+			responseWriter.WriteHeader(status)
+			_, _ = responseWriter.Write([]byte(`{"message": "Server Error"}`))
+
+			return
+		}
+
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.2.3"})
+	}))
+
+	return server, &requests
+}
+
+func newRetryingClient(baseURL string, slept *[]time.Duration) *ClientImpl {
+	config := DefaultClientConfig()
+	config.BaseURL = baseURL
+
+	client := NewClientWithConfig(config)
+	client.sleep = func(_ context.Context, duration time.Duration) error {
+		*slept = append(*slept, duration)
+
+		return nil
+	}
+
+	return client
+}
+
+func TestGetLatestRelease_RetriesServerErrors(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	server, requests := flakyServer(t, 2, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	var slept []time.Duration
+
+	client := newRetryingClient(server.URL, &slept)
+
+	result, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Name != "v1.2.3" {
+		t.Errorf("Expected release name 'v1.2.3', got '%s'", result.Name)
+	}
+
+	if *requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", *requests)
+	}
+
+	// Equal jitter keeps between half and all of the 1s and 2s backoffs
+	if len(slept) != 2 ||
+		slept[0] < 500*time.Millisecond || slept[0] > time.Second ||
+		slept[1] < time.Second || slept[1] > 2*time.Second {
+		t.Errorf("Unexpected backoff %v", slept)
+	}
+}
+
+func TestGetLatestRelease_RetriesAttemptTimeout(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	requests := 0
+
+	// The first response stalls before sending its headers
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		requests++
+
+		if requests == 1 {
+			select {
+			case <-time.After(time.Second):
+			case <-request.Context().Done():
+				return
+			}
+		}
+
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.2.3"})
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+
+	client := newRetryingClient(server.URL, &slept)
+	client.retry.AttemptTimeout = 50 * time.Millisecond
+
+	result, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Name != "v1.2.3" {
+		t.Errorf("Expected release name 'v1.2.3', got '%s'", result.Name)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestDownloadReleaseAsset_AttemptTimeoutExcludesBody(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	requests := 0
+
+	// The headers arrive promptly, but the body takes longer than the attempt timeout
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		_, _ = responseWriter.Write([]byte("slow "))
+		responseWriter.(http.Flusher).Flush()
+
+		time.Sleep(150 * time.Millisecond)
+
+		_, _ = responseWriter.Write([]byte("asset"))
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+
+	client := newRetryingClient(server.URL, &slept)
+	client.downloadBaseURL = server.URL
+	client.retry.AttemptTimeout = 50 * time.Millisecond
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "owner", "repo", "v1.2.3", "app")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "slow asset" {
+		t.Errorf("Expected asset contents 'slow asset', got '%s'", string(result))
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestGetLatestRelease_RetryAfter(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	server, requests := flakyServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"7"}})
+	defer server.Close()
+
+	var slept []time.Duration
+
+	client := newRetryingClient(server.URL, &slept)
+
+	_, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if *requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", *requests)
+	}
+
+	if len(slept) != 1 || slept[0] != 7*time.Second {
+		t.Errorf("Expected to sleep for 7s, slept %v", slept)
+	}
+}
+
+func TestGetLatestRelease_RetriesExhausted(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	server, requests := flakyServer(t, 5, http.StatusInternalServerError, nil)
+	defer server.Close()
+
+	var slept []time.Duration
+
+	client := newRetryingClient(server.URL, &slept)
+
+	_, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "Server Error") {
+		t.Errorf("Expected the last response's error, got '%s'", err.Error())
+	}
+
+	if *requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", *requests)
+	}
+}
+
+func TestGetLatestRelease_DoesNotRetryClientErrors(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	server, requests := flakyServer(t, 5, http.StatusNotFound, nil)
+	defer server.Close()
+
+	var slept []time.Duration
+
+	client := newRetryingClient(server.URL, &slept)
+
+	_, err := client.GetLatestRelease(t.Context(), "owner", "repo")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if *requests != 1 {
+		t.Errorf("Expected 1 request, got %d", *requests)
+	}
+}
+
+func TestGetLatestRelease_CancelledWhileWaitingToRetry(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+
+	server, requests := flakyServer(t, 5, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	// Cancel once the first attempt fails, as a signal would
+	client.sleep = func(ctx context.Context, duration time.Duration) error {
+		cancel()
+
+		return sleepContext(ctx, duration)
+	}
+
+	_, err := client.GetLatestRelease(ctx, "owner", "repo")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if *requests != 1 {
+		t.Errorf("Expected 1 request, got %d", *requests)
+	}
+}
+
+func TestGetLatestReleases_DoesNotRetryPosts(t *testing.T) {
+	t.Setenv("GH_TOKEN", "test-token")
+
+	server, requests := flakyServer(t, 5, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	var slept []time.Duration
+
+	client := newRetryingClient(server.URL, &slept)
+	client.graphQLURL = server.URL + "/graphql"
+
+	_, err := client.GetLatestReleases(t.Context(), []Repository{{Owner: "owner", Name: "repo"}})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if *requests != 1 || len(slept) != 0 {
+		t.Errorf("Expected 1 request and no backoff, got %d requests and slept %v", *requests, slept)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	client := NewClientWithConfig(ClientConfig{
+		Retry: RetryConfig{InitialBackoff: 24 * time.Hour, MaxBackoff: 30 * time.Second},
+	})
+
+	// Doublings of the initial backoff beyond the maximum would overflow
+	for _, attempt := range []int{1, 2, 40, 64, 100} {
+		delay := client.backoff(attempt, nil)
+		if delay < 15*time.Second || delay > 30*time.Second {
+			t.Errorf("Expected attempt %d to back off between 15s and 30s, got %v", attempt, delay)
+		}
+	}
+
+	client.retry = RetryConfig{InitialBackoff: -time.Second, MaxBackoff: -time.Second}
+
+	if delay := client.backoff(1, nil); delay != 0 {
+		t.Errorf("Expected no backoff for a negative config, got %v", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if delay, ok := parseRetryAfter("120", now); !ok || delay != 2*time.Minute {
+		t.Errorf("Expected 2m, got %s", delay)
+	}
+
+	date := now.Add(90 * time.Second).Format(http.TimeFormat)
+	if delay, ok := parseRetryAfter(date, now); !ok || delay != 90*time.Second {
+		t.Errorf("Expected 1m30s, got %s", delay)
+	}
+
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected an invalid value to be ignored")
+	}
+}
@@ -0,0 +1,44 @@
+package github
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// URLRewrite redirects requests for URLs beginning with From to the same path beneath To,
+// such as to route downloads through an artifact proxy.
+type URLRewrite struct {
+	From string
+	To   string
+
+	// TokenEnvVar names the environment variable holding a bearer token for the mirror.
+	// When empty, credentials for the mirror's host are read from netrc.
+	TokenEnvVar string
+}
+
+// rewriteURL applies the first rewrite rule matching url.
+func rewriteURL(rewrites []URLRewrite, url string) (string, *URLRewrite) {
+	for idx := range rewrites {
+		if rest, ok := strings.CutPrefix(url, rewrites[idx].From); ok {
+			return rewrites[idx].To + rest, &rewrites[idx]
+		}
+	}
+
+	return url, nil
+}
+
+// authorize adds the mirror's credentials to a request.
+func (r *URLRewrite) authorize(req *http.Request) {
+	if r.TokenEnvVar != "" {
+		if token := os.Getenv(r.TokenEnvVar); token != "" {
+			req.Header.Add("Authorization", "Bearer "+token)
+		}
+
+		return
+	}
+
+	if login, password, ok := netrcCredentials(req.URL.Hostname(), true); ok {
+		req.SetBasicAuth(login, password)
+	}
+}
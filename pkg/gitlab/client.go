@@ -0,0 +1,255 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/noizwaves/grab/pkg/github"
+)
+
+const (
+	// DefaultHost is used when a package does not specify a GitLab host.
+	DefaultHost = "gitlab.com"
+
+	// releasesPerPage is the maximum page size supported by the GitLab API.
+	releasesPerPage = 100
+
+	// maxRedirects matches the limit of net/http's default redirect policy.
+	maxRedirects = 10
+)
+
+func parseRelease(data []byte) (*Release, error) {
+	var output Release
+
+	err := json.Unmarshal(data, &output)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response as JSON: %w", err)
+	}
+
+	return &output, nil
+}
+
+func parseReleases(data []byte) ([]Release, error) {
+	var output []Release
+
+	err := json.Unmarshal(data, &output)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response as JSON: %w", err)
+	}
+
+	return output, nil
+}
+
+type Client interface {
+	GetLatestRelease(ctx context.Context, project string) (*Release, error)
+	GetReleaseByTag(ctx context.Context, project, tag string) (*Release, error)
+	ListReleases(ctx context.Context, project string) ([]Release, error)
+	DownloadReleaseAsset(ctx context.Context, project, tag, assetName string) ([]byte, error)
+}
+
+type ClientImpl struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      github.RetryConfig
+
+	// sleep waits between retries, and is replaced in tests
+	sleep func(ctx context.Context, duration time.Duration) error
+}
+
+// ClientConfig describes how to reach a GitLab instance.
+type ClientConfig struct {
+	// BaseURL is the root of the REST API (e.g. https://gitlab.com/api/v4)
+	BaseURL string
+
+	// Retry controls how failed requests are retried, as for GitHub clients
+	Retry github.RetryConfig
+
+	// HTTPClient performs requests, defaulting to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// DefaultClientConfig returns the configuration for the GitLab instance at host, defaulting to gitlab.com.
+func DefaultClientConfig(host string) ClientConfig {
+	if host == "" {
+		host = DefaultHost
+	}
+
+	return ClientConfig{
+		BaseURL: "https://" + host + "/api/v4",
+		Retry:   github.DefaultRetryConfig(),
+	}
+}
+
+// NewClient creates a client for the GitLab instance at host (e.g. gitlab.com), making requests with httpClient.
+func NewClient(host string, httpClient *http.Client) *ClientImpl {
+	config := DefaultClientConfig(host)
+	config.HTTPClient = httpClient
+
+	return NewClientWithConfig(config)
+}
+
+func NewClientWithBaseURL(baseURL string) *ClientImpl {
+	return NewClientWithConfig(ClientConfig{BaseURL: baseURL})
+}
+
+func NewClientWithConfig(config ClientConfig) *ClientImpl {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &ClientImpl{
+		baseURL:    config.BaseURL,
+		httpClient: httpClient,
+		retry:      config.Retry,
+		sleep:      sleepContext,
+	}
+}
+
+func (g *ClientImpl) GetLatestRelease(ctx context.Context, project string) (*Release, error) {
+	url := fmt.Sprintf("%s/projects/%s/releases/permalink/latest", g.baseURL, projectID(project))
+
+	data, err := g.getAPI(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRelease(data)
+}
+
+func (g *ClientImpl) GetReleaseByTag(ctx context.Context, project, tag string) (*Release, error) {
+	url := fmt.Sprintf("%s/projects/%s/releases/%s", g.baseURL, projectID(project), url.PathEscape(tag))
+
+	data, err := g.getAPI(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRelease(data)
+}
+
+// ListReleases returns the most recent releases of a project, newest first.
+// Only the first page of results is requested.
+func (g *ClientImpl) ListReleases(ctx context.Context, project string) ([]Release, error) {
+	url := fmt.Sprintf("%s/projects/%s/releases?per_page=%d", g.baseURL, projectID(project), releasesPerPage)
+
+	data, err := g.getAPI(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReleases(data)
+}
+
+// DownloadReleaseAsset downloads the asset link named assetName from the release tagged tag.
+func (g *ClientImpl) DownloadReleaseAsset(ctx context.Context, project, tag, assetName string) ([]byte, error) {
+	release, err := g.GetReleaseByTag(ctx, project, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching release %q: %w", tag, err)
+	}
+
+	for _, link := range release.Assets.Links {
+		if link.Name != assetName {
+			continue
+		}
+
+		assetURL := link.DirectAssetURL
+		if assetURL == "" {
+			assetURL = link.URL
+		}
+
+		slog.DebugContext(ctx, "Downloading asset from GitLab", "url", assetURL)
+
+		return g.download(ctx, assetURL)
+	}
+
+	return nil, fmt.Errorf("asset %q not found in release %q", assetName, tag)
+}
+
+// getAPI performs a GET request against the GitLab API and returns the body of a successful response.
+func (g *ClientImpl) getAPI(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GET request: %w", err)
+	}
+
+	req.Header.Add("Accept", "application/json")
+	addAuthorization(req)
+
+	resp, data, err := g.do(g.httpClient, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp, data)
+	}
+
+	return data, nil
+}
+
+func (g *ClientImpl) download(ctx context.Context, assetURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, assetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Only send credentials to the GitLab instance itself
+	if sameHost(assetURL, g.baseURL) {
+		addAuthorization(req)
+	}
+
+	// Asset links redirect to their external URL, which must not receive the token.
+	// net/http only strips standard credential headers when a redirect changes host.
+	client := *g.httpClient
+	client.CheckRedirect = func(redirect *http.Request, via []*http.Request) error {
+		if redirect.URL.Host != via[0].URL.Host {
+			redirect.Header.Del("PRIVATE-TOKEN")
+		}
+
+		if g.httpClient.CheckRedirect != nil {
+			return g.httpClient.CheckRedirect(redirect, via)
+		}
+
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		return nil
+	}
+
+	resp, data, err := g.do(&client, req, true)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting asset: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status downloading asset: %w", parseError(resp, data))
+	}
+
+	return data, nil
+}
+
+func addAuthorization(req *http.Request) {
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		req.Header.Add("PRIVATE-TOKEN", token)
+	}
+}
+
+// projectID encodes a project path (e.g. gitlab-org/cli) for use as an API path parameter.
+func projectID(project string) string {
+	return url.PathEscape(project)
+}
+
+func sameHost(a, b string) bool {
+	parsedA, errA := url.Parse(a)
+	parsedB, errB := url.Parse(b)
+
+	return errA == nil && errB == nil && parsedA.Host == parsedB.Host
+}
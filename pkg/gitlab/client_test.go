@@ -0,0 +1,342 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetLatestRelease_Success(t *testing.T) {
+	releasedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Mock GitLab API response
+	mockRelease := Release{
+		Name:        "v1.40.0",
+		TagName:     "v1.40.0",
+		Description: "Bug fixes",
+		ReleasedAt:  releasedAt,
+		Links: ReleaseLinks{
+			Self: "https://gitlab.com/gitlab-org/cli/-/releases/v1.40.0",
+		},
+	}
+
+	// Create mock server
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		// Verify request
+		if request.URL.EscapedPath() != "/projects/gitlab-org%2Fcli/releases/permalink/latest" {
+			t.Errorf("Expected escaped project path, got '%s'", request.URL.EscapedPath())
+		}
+
+		if request.Header.Get("PRIVATE-TOKEN") != "secret" {
+			t.Errorf("Expected PRIVATE-TOKEN header 'secret', got '%s'", request.Header.Get("PRIVATE-TOKEN"))
+		}
+
+		// Return mock response
+		responseWriter.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(responseWriter).Encode(mockRelease)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.GetLatestRelease(t.Context(), "gitlab-org/cli")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Name != "v1.40.0" {
+		t.Errorf("Expected release name 'v1.40.0', got '%s'", result.Name)
+	}
+
+	if result.Description != "Bug fixes" {
+		t.Errorf("Expected description 'Bug fixes', got '%s'", result.Description)
+	}
+
+	if !result.ReleasedAt.Equal(releasedAt) {
+		t.Errorf("Expected released at %v, got %v", releasedAt, result.ReleasedAt)
+	}
+
+	if result.Links.Self != "https://gitlab.com/gitlab-org/cli/-/releases/v1.40.0" {
+		t.Errorf("Expected self link, got '%s'", result.Links.Self)
+	}
+}
+
+func TestGetReleaseByTag_NotFound(t *testing.T) {
+	// Create mock server that returns 404
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(responseWriter).Encode(map[string]string{
+			"message": "404 Not Found",
+		})
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	_, err := client.GetReleaseByTag(t.Context(), "gitlab-org/cli", "nonexistent")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if err.Error() != "404 Not Found" {
+		t.Errorf("Expected error message '404 Not Found', got '%s'", err.Error())
+	}
+}
+
+func TestListReleases_Success(t *testing.T) {
+	// Mock GitLab API response
+	mockReleases := []Release{
+		{Name: "v1.40.0", TagName: "v1.40.0"},
+		{Name: "v1.39.0", TagName: "v1.39.0"},
+	}
+
+	// Create mock server
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		// Verify request
+		if request.URL.EscapedPath() != "/projects/gitlab-org%2Fcli/releases" {
+			t.Errorf("Expected releases path, got '%s'", request.URL.EscapedPath())
+		}
+
+		if request.URL.Query().Get("per_page") != "100" {
+			t.Errorf("Expected per_page '100', got '%s'", request.URL.Query().Get("per_page"))
+		}
+
+		// Return mock response
+		responseWriter.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(responseWriter).Encode(mockReleases)
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.ListReleases(t.Context(), "gitlab-org/cli")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(result))
+	}
+}
+
+func TestDownloadReleaseAsset_Success(t *testing.T) {
+	var server *httptest.Server
+
+	// Create mock server serving both the API and the package registry
+	server = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		switch request.URL.EscapedPath() {
+		case "/projects/gitlab-org%2Fcli/releases/v1.40.0":
+			_ = json.NewEncoder(responseWriter).Encode(Release{
+				Name:    "v1.40.0",
+				TagName: "v1.40.0",
+				Assets: Assets{
+					Links: []AssetLink{
+						{Name: "glab_1.40.0_darwin_arm64.tar.gz", URL: server.URL + "/other"},
+						{
+							Name:           "glab_1.40.0_linux_amd64.tar.gz",
+							URL:            server.URL + "/redirect",
+							DirectAssetURL: server.URL + "/packages/glab_1.40.0_linux_amd64.tar.gz",
+						},
+					},
+				},
+			})
+		case "/packages/glab_1.40.0_linux_amd64.tar.gz":
+			if request.Header.Get("PRIVATE-TOKEN") != "secret" {
+				t.Errorf("Expected PRIVATE-TOKEN header 'secret', got '%s'", request.Header.Get("PRIVATE-TOKEN"))
+			}
+
+			_, _ = responseWriter.Write([]byte("asset contents"))
+		default:
+			t.Errorf("Unexpected request to '%s'", request.URL.EscapedPath())
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "gitlab-org/cli", "v1.40.0", "glab_1.40.0_linux_amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "asset contents" {
+		t.Errorf("Expected asset contents, got '%s'", string(result))
+	}
+}
+
+func TestDownloadReleaseAsset_MissingAsset(t *testing.T) {
+	// Create mock server with a release without the requested asset
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.40.0", TagName: "v1.40.0"})
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock server
+	client := NewClientWithBaseURL(server.URL)
+
+	_, err := client.DownloadReleaseAsset(t.Context(), "gitlab-org/cli", "v1.40.0", "glab.tar.gz")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if err.Error() != `asset "glab.tar.gz" not found in release "v1.40.0"` {
+		t.Errorf("Unexpected error message '%s'", err.Error())
+	}
+}
+
+func TestDownloadReleaseAsset_RedirectDropsToken(t *testing.T) {
+	// Create mock server for the external host that release links redirect to
+	external := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("PRIVATE-TOKEN") != "" {
+			t.Errorf("Expected no PRIVATE-TOKEN header on another host, got '%s'", request.Header.Get("PRIVATE-TOKEN"))
+		}
+
+		_, _ = responseWriter.Write([]byte("external contents"))
+	}))
+	defer external.Close()
+
+	// Create mock server redirecting the direct asset URL, as GitLab does
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		switch request.URL.EscapedPath() {
+		case "/projects/gitlab-org%2Fcli/releases/v1.40.0":
+			_ = json.NewEncoder(responseWriter).Encode(Release{
+				TagName: "v1.40.0",
+				Assets: Assets{
+					Links: []AssetLink{{
+						Name:           "glab.tar.gz",
+						URL:            external.URL + "/glab.tar.gz",
+						DirectAssetURL: "http://" + request.Host + "/-/releases/v1.40.0/downloads/glab.tar.gz",
+					}},
+				},
+			})
+		case "/-/releases/v1.40.0/downloads/glab.tar.gz":
+			if request.Header.Get("PRIVATE-TOKEN") != "secret" {
+				t.Errorf("Expected PRIVATE-TOKEN header 'secret', got '%s'", request.Header.Get("PRIVATE-TOKEN"))
+			}
+
+			http.Redirect(responseWriter, request, external.URL+"/glab.tar.gz", http.StatusFound)
+		default:
+			t.Errorf("Unexpected request to '%s'", request.URL.EscapedPath())
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.DownloadReleaseAsset(t.Context(), "gitlab-org/cli", "v1.40.0", "glab.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(result) != "external contents" {
+		t.Errorf("Expected external contents, got '%s'", string(result))
+	}
+}
+
+func TestGetReleaseByTag_EscapesTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.EscapedPath() != "/projects/gitlab-org%2Fcli/releases/v1.0%2Frc%23b" {
+			t.Errorf("Unexpected request to '%s'", request.URL.EscapedPath())
+		}
+
+		_ = json.NewEncoder(responseWriter).Encode(Release{TagName: "v1.0/rc#b"})
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.GetReleaseByTag(t.Context(), "gitlab-org/cli", "v1.0/rc#b")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.TagName != "v1.0/rc#b" {
+		t.Errorf("Expected tag name 'v1.0/rc#b', got '%s'", result.TagName)
+	}
+}
+
+func TestGetReleaseByTag_PlainTextError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		responseWriter.WriteHeader(http.StatusNotFound)
+		_, _ = responseWriter.Write([]byte("no such release\n"))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+
+	_, err := client.GetReleaseByTag(t.Context(), "gitlab-org/cli", "nonexistent")
+	if err == nil || err.Error() != "no such release" {
+		t.Errorf("Expected the plain text body as the message, got %v", err)
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetLatestRelease_RetriesServerErrors(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		if requests <= 2 {
+			responseWriter.Header().Set("Content-Type", "text/html")
+			responseWriter.WriteHeader(http.StatusBadGateway)
+			_, _ = responseWriter.Write([]byte("<html>Bad Gateway</html>"))
+
+			return
+		}
+
+		_ = json.NewEncoder(responseWriter).Encode(Release{Name: "v1.40.0"})
+	}))
+	defer server.Close()
+
+	config := DefaultClientConfig("")
+	config.BaseURL = server.URL
+
+	var slept []time.Duration
+
+	client := NewClientWithConfig(config)
+	client.sleep = func(_ context.Context, duration time.Duration) error {
+		slept = append(slept, duration)
+
+		return nil
+	}
+
+	result, err := client.GetLatestRelease(t.Context(), "gitlab-org/cli")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Name != "v1.40.0" || requests != 3 || len(slept) != 2 {
+		t.Errorf("Expected a release after 3 requests and 2 backoffs, got %+v after %d requests and %v",
+			result, requests, slept)
+	}
+
+	// Without retries, the status of an HTML error page is reported
+	client.retry.MaxAttempts = 1
+	requests = 0
+
+	_, err = client.GetLatestRelease(t.Context(), "gitlab-org/cli")
+	if err == nil || err.Error() != "502 Bad Gateway" {
+		t.Errorf("Expected error message '502 Bad Gateway', got %v", err)
+	}
+}
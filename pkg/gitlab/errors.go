@@ -0,0 +1,68 @@
+package gitlab
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// maxPlainTextMessage bounds how much of a plain text error body is kept as the message.
+const maxPlainTextMessage = 200
+
+// ErrNotFound is matched with errors.Is by responses reporting that a project, release or asset does not exist.
+var ErrNotFound = errors.New("not found")
+
+// APIError reports an unsuccessful response from the API.
+type APIError struct {
+	StatusCode int
+	URL        string
+
+	// Message is the server's explanation, or the status when there is none
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Unwrap classifies the error by its status code.
+func (e *APIError) Unwrap() error {
+	if e.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+type errorBody struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// parseError describes an unsuccessful response, using the message of a JSON error body, or else a plain text body,
+// when there is one. Proxies and load balancers commonly answer with HTML or plain text.
+func parseError(resp *http.Response, data []byte) error {
+	var body errorBody
+
+	message := ""
+	if json.Unmarshal(data, &body) == nil {
+		message = cmp.Or(body.Message, body.Error)
+	} else if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		message = strings.TrimSpace(string(data))
+		if len(message) > maxPlainTextMessage {
+			message = message[:maxPlainTextMessage] + "..."
+		}
+	}
+
+	if message == "" {
+		message = resp.Status
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		URL:        resp.Request.URL.Redacted(),
+		Message:    message,
+	}
+}
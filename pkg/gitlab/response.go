@@ -0,0 +1,32 @@
+package gitlab
+
+import "time"
+
+// Release represents a GitLab release with its asset links.
+type Release struct {
+	Name        string       `json:"name"`
+	TagName     string       `json:"tag_name"` //nolint:tagliatelle
+	Description string       `json:"description"`
+	ReleasedAt  time.Time    `json:"released_at"`      //nolint:tagliatelle
+	Upcoming    bool         `json:"upcoming_release"` //nolint:tagliatelle
+	Links       ReleaseLinks `json:"_links"`           //nolint:tagliatelle
+	Assets      Assets       `json:"assets"`
+}
+
+// ReleaseLinks contains the web URLs of a release.
+type ReleaseLinks struct {
+	Self string `json:"self"`
+}
+
+// Assets contains the files attached to a release.
+type Assets struct {
+	Links []AssetLink `json:"links"`
+}
+
+// AssetLink represents a release asset link, such as a generic package registry file.
+type AssetLink struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"` //nolint:tagliatelle
+}
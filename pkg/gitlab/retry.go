@@ -0,0 +1,88 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/noizwaves/grab/pkg/progress"
+)
+
+// do performs a GET request with client and reads the response body, reporting the progress of successful downloads.
+// Network errors and transient server errors are retried with jittered exponential backoff.
+func (g *ClientImpl) do(client *http.Client, req *http.Request, download bool) (*http.Response, []byte, error) {
+	ctx := req.Context()
+
+	attempts := max(g.retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		slog.DebugContext(ctx, "Sending request", "url", req.URL.Redacted(), "attempt", attempt)
+
+		resp, data, err := g.attemptRequest(client, req, download)
+		if attempt >= attempts || !shouldRetry(resp, err) {
+			return resp, data, err
+		}
+
+		delay := g.retry.Backoff(attempt)
+
+		slog.DebugContext(ctx, "Request failed, retrying", "url", req.URL.Redacted(), "attempt", attempt, "delay", delay)
+
+		err = g.sleep(ctx, delay)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func (g *ClientImpl) attemptRequest(
+	client *http.Client, req *http.Request, download bool,
+) (*http.Response, []byte, error) {
+	resp, release, err := g.retry.Send(client, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer release()
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if download && resp.StatusCode == http.StatusOK {
+		body = progress.NewReader(req.Context(), resp.Body, resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return resp, data, nil
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepContext waits for duration, returning early with an error if ctx is cancelled.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting to retry: %w", ctx.Err())
+	}
+}
@@ -2,12 +2,14 @@ package importer
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -17,32 +19,67 @@ import (
 
 type Importer struct {
 	githubClient github.Client
+
+	// sourceKind is the kind of source that packages are imported from
+	sourceKind string
+
+	// host serves the repositories being imported
+	host string
+
+	// Platforms are the pairs to import, all of which must have assets
+	// (default: Linux and macOS on amd64 and arm64, plus any other supported pairs with assets)
+	Platforms []PlatformPair
+
+	// BestEffort imports the pairs that have assets, rather than failing when a required pair has none
+	BestEffort bool
 }
 
 func NewImporter(githubClient github.Client) *Importer {
 	return &Importer{
 		githubClient: githubClient,
+		sourceKind:   pkg.SourceKindGitHub,
+		host:         github.DefaultHost,
 	}
 }
 
-func (i *Importer) Import(gCtx *pkg.GrabContext, url string, customPackageName string, out io.Writer) error {
-	_, err := i.ImportPackage(gCtx, url, customPackageName, out)
+// NewGitHubEnterpriseImporter creates an importer for repositories on the GitHub Enterprise Server instance at host.
+func NewGitHubEnterpriseImporter(githubClient github.Client, host string) *Importer {
+	return &Importer{
+		githubClient: githubClient,
+		sourceKind:   pkg.SourceKindGitHub,
+		host:         host,
+	}
+}
+
+// NewGiteaImporter creates an importer for repositories on the Gitea or Forgejo instance at host.
+func NewGiteaImporter(giteaClient github.Client, host string) *Importer {
+	return &Importer{
+		githubClient: giteaClient,
+		sourceKind:   pkg.SourceKindGitea,
+		host:         host,
+	}
+}
+
+func (i *Importer) Import(
+	ctx context.Context, gCtx *pkg.GrabContext, url string, customPackageName string, out io.Writer,
+) error {
+	_, err := i.ImportPackage(ctx, gCtx, url, customPackageName, out)
 
 	return err
 }
 
 func (i *Importer) ImportPackage(
-	gCtx *pkg.GrabContext, url string, customPackageName string, out io.Writer,
+	ctx context.Context, gCtx *pkg.GrabContext, url string, customPackageName string, out io.Writer,
 ) (*ImportResult, error) {
-	releaseURL, err := ParseGitHubReleaseURL(url)
+	releaseURL, err := ParseReleaseURL(url, i.host)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx := context.Background()
-	slog.InfoContext(ctx, "Importing GitHub Release", "org", releaseURL.Organization, "repo", releaseURL.Repository)
+	slog.InfoContext(ctx, "Importing release",
+		"source", i.sourceKind, "org", releaseURL.Organization, "repo", releaseURL.Repository)
 
-	release, err := i.githubClient.GetLatestRelease(releaseURL.Organization, releaseURL.Repository)
+	release, err := i.githubClient.GetLatestRelease(ctx, releaseURL.Organization, releaseURL.Repository)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get release: %w", err)
 	}
@@ -53,17 +90,20 @@ func (i *Importer) ImportPackage(
 	}
 
 	detectedPackage, err := detectPackage(
+		ctx,
 		i.githubClient,
 		releaseURL.Organization,
 		releaseURL.Repository,
 		release,
 		packageName,
+		i.pairs(),
+		i.BestEffort,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	packageConfig := buildPackageConfig(packageName, releaseURL, detectedPackage)
+	packageConfig := buildPackageConfig(i.sourceKind, packageName, releaseURL, detectedPackage)
 
 	packagePath, err := gCtx.SavePackage(&packageConfig)
 	if err != nil {
@@ -72,33 +112,63 @@ func (i *Importer) ImportPackage(
 
 	fmt.Fprintf(out, "Package %q saved to %s\n", packageName, packagePath)
 
+	if len(detectedPackage.missing) > 0 {
+		fmt.Fprintf(out, "No assets found for %s, so %q will not install there\n",
+			joinPairs(detectedPackage.missing), packageName)
+	}
+
 	return &ImportResult{
 		PackageName: packageName,
 		Version:     detectedPackage.version,
 	}, nil
 }
 
-func buildPackageConfig(packageName string, releaseURL *GitHubReleaseURL, detected *detectedPackage) pkg.ConfigPackage {
+func buildPackageConfig(
+	sourceKind, packageName string, releaseURL *GitHubReleaseURL, detected *detectedPackage,
+) pkg.ConfigPackage {
+	spec := pkg.ConfigPackageSpec{
+		Program: pkg.ConfigProgram{
+			VersionArgs:  []string{"--version"},
+			VersionRegex: detected.versionRegex,
+		},
+	}
+
+	switch sourceKind {
+	case pkg.SourceKindGitea:
+		spec.GiteaRelease = &pkg.ConfigGiteaRelease{
+			Host:               releaseURL.Host,
+			Org:                releaseURL.Organization,
+			Repo:               releaseURL.Repository,
+			Name:               detected.releaseName,
+			VersionRegex:       detected.versionRegex,
+			FileName:           detected.assets,
+			EmbeddedBinaryPath: detected.embeddedBinaryPaths,
+		}
+	default:
+		host := releaseURL.Host
+		if host == github.DefaultHost {
+			// github.com is implied when no host is given
+			host = ""
+		}
+
+		spec.GitHubRelease = &pkg.ConfigGitHubRelease{
+			Host:               host,
+			Org:                releaseURL.Organization,
+			Repo:               releaseURL.Repository,
+			Name:               detected.releaseName,
+			VersionRegex:       detected.versionRegex,
+			FileName:           detected.assets,
+			EmbeddedBinaryPath: detected.embeddedBinaryPaths,
+		}
+	}
+
 	return pkg.ConfigPackage{
 		APIVersion: "grab.noizwaves.com/v1alpha1",
 		Kind:       "Package",
 		Metadata: pkg.ConfigPackageMetadata{
 			Name: packageName,
 		},
-		Spec: pkg.ConfigPackageSpec{
-			GitHubRelease: pkg.ConfigGitHubRelease{
-				Org:                releaseURL.Organization,
-				Repo:               releaseURL.Repository,
-				Name:               detected.releaseName,
-				VersionRegex:       detected.versionRegex,
-				FileName:           detected.assets,
-				EmbeddedBinaryPath: detected.embeddedBinaryPaths,
-			},
-			Program: pkg.ConfigProgram{
-				VersionArgs:  []string{"--version"},
-				VersionRegex: detected.versionRegex,
-			},
-		},
+		Spec: spec,
 	}
 }
 
@@ -108,6 +178,9 @@ type detectedPackage struct {
 	version             string
 	assets              map[string]string
 	embeddedBinaryPaths map[string]string
+
+	// missing are the required pairs without assets, when importing on a best effort basis
+	missing []PlatformPair
 }
 
 // ImportResult contains the result of a successful import operation.
@@ -116,8 +189,16 @@ type ImportResult struct {
 	Version     string
 }
 
-//nolint:funlen,lll
-func detectPackage(ghClient github.Client, org, repo string, release *github.Release, packageName string) (*detectedPackage, error) {
+//nolint:funlen,cyclop
+func detectPackage(
+	ctx context.Context,
+	ghClient github.Client,
+	org, repo string,
+	release *github.Release,
+	packageName string,
+	pairs []PlatformPair,
+	bestEffort bool,
+) (*detectedPackage, error) {
 	// Release name pattern
 	releaseDetector := NewReleaseNamePatternDetector()
 
@@ -139,55 +220,78 @@ func detectPackage(ghClient github.Client, org, repo string, release *github.Rel
 
 	fileNames := make(map[string]string)
 
-	// analyze the asset names for all required platform+architecture pairs
-	detectablePairs := getDetectablePairs()
-
-	for _, pair := range detectablePairs {
-		platform := pair[0]
-		arch := pair[1]
-		platformDetector := NewPlatformPatternDetector(platform)
-		archDetector := NewArchitecturePatternDetector(arch)
+	var missing []PlatformPair
 
-		var result string
+	// analyze the asset names for all platform+architecture pairs, of which only some are required
+	for _, pair := range pairs {
+		result, err := detectAssetName(release.Assets, pair)
+		if err != nil {
+			return nil, err
+		}
 
-		for _, asset := range release.Assets {
-			_, err := platformDetector.AnalyzeOne(asset.Name)
-			if err != nil {
-				continue
-			}
+		switch {
+		case result == "" && pair.required && !bestEffort:
+			return nil, fmt.Errorf("no matching asset name found for platform %s and architecture %s",
+				pair.Platform, pair.Arch)
+		case result == "" && pair.required:
+			missing = append(missing, pair)
+		case result == "":
+			slog.DebugContext(ctx, "No asset found for optional platform", "platform", pair.Platform, "arch", pair.Arch)
+		default:
+			// Convert to a template string if needed
+			fileNames[pair.key()] = UnrenderVersionValue(result, latestVersion)
+		}
+	}
 
-			_, err = archDetector.AnalyzeOne(asset.Name)
-			if err != nil {
-				continue
-			}
+	if len(fileNames) == 0 {
+		return nil, fmt.Errorf("no matching asset names found for %s", joinPairs(pairs))
+	}
 
-			// this asset name matches the current pair
-			result = asset.Name
+	// Detect embedded binary paths for archive assets. Only the archives of required pairs are downloaded,
+	// so that a default import does not fetch an archive for every optional pair.
+	requiredAssets := make(map[string]string)
 
-			break
+	for _, pair := range pairs {
+		if assetName, ok := fileNames[pair.key()]; ok && pair.required {
+			requiredAssets[pair.key()] = assetName
 		}
+	}
 
-		if result == "" {
-			return nil, fmt.Errorf("no matching asset name found for platform %s and architecture %s", platform, arch)
-		}
+	embeddedPaths, failures := detectEmbeddedBinaryPaths(ctx, ghClient, org, repo, release, packageName,
+		requiredAssets, latestVersion)
 
-		// Convert to a template string if needed
-		result = UnrenderVersionValue(result, latestVersion)
+	for _, pair := range pairs {
+		if err, failed := failures[pair.key()]; failed {
+			// Continue with the default path rather than failing completely
+			slog.WarnContext(ctx, "Failed to detect embedded binary path", "platform", pair.Platform,
+				"arch", pair.Arch, "error", err)
+		}
+	}
 
-		key := fmt.Sprintf("%s,%s", platform, arch)
-		fileNames[key] = result
+	paths := make(map[string]string)
+	if embeddedPaths != nil {
+		paths = *embeddedPaths
 	}
 
-	// Detect embedded binary paths for archive assets
-	embeddedPaths, err := detectEmbeddedBinaryPaths(ghClient, org, repo, release, packageName, fileNames, latestVersion)
+	err = applySharedEmbeddedPath(ctx, paths, failures, fileNames, pairs, packageName, latestVersion)
 	if err != nil {
-		slog.WarnContext(context.Background(), "Failed to detect embedded binary paths", "error", err)
-		// Continue without embedded paths rather than failing completely
+		return nil, err
 	}
 
+	if len(fileNames) == 0 {
+		return nil, fmt.Errorf("no assets could be analyzed for %s", joinPairs(pairs))
+	}
+
+	// Once any pair has an embedded path, every pair needs one
 	var embeddedBinaryPathsMap map[string]string
-	if embeddedPaths != nil {
-		embeddedBinaryPathsMap = *embeddedPaths
+	if len(paths) > 0 {
+		for key := range fileNames {
+			if _, ok := paths[key]; !ok {
+				paths[key] = packageName
+			}
+		}
+
+		embeddedBinaryPathsMap = paths
 	}
 
 	return &detectedPackage{
@@ -196,64 +300,199 @@ func detectPackage(ghClient github.Client, org, repo string, release *github.Rel
 		assets:              fileNames,
 		versionRegex:        versionRegex,
 		embeddedBinaryPaths: embeddedBinaryPathsMap,
+		missing:             missing,
 	}, nil
 }
 
-func getDetectablePairs() [][]string {
-	return [][]string{
-		{"linux", "amd64"},
-		{"linux", "arm64"},
-		{"darwin", "amd64"},
-		{"darwin", "arm64"},
+// applySharedEmbeddedPath gives the archives of optional pairs the embedded path that the analyzed archives of
+// the required pairs share. Optional archives are dropped when those paths differ, as they were not analyzed.
+func applySharedEmbeddedPath(
+	ctx context.Context,
+	paths map[string]string,
+	failures map[string]error,
+	fileNames map[string]string,
+	pairs []PlatformPair,
+	packageName, versionLiteral string,
+) error {
+	var (
+		requiredPaths    []string
+		optionalArchives []PlatformPair
+	)
+
+	for _, pair := range pairs {
+		assetName, ok := fileNames[pair.key()]
+		if !ok {
+			continue
+		}
+
+		archive, err := isArchiveTemplate(assetName, versionLiteral)
+		if err != nil {
+			return err
+		}
+
+		_, failed := failures[pair.key()]
+
+		switch {
+		case !archive || failed:
+			continue
+		case pair.required:
+			requiredPaths = append(requiredPaths, cmp.Or(paths[pair.key()], packageName))
+		default:
+			optionalArchives = append(optionalArchives, pair)
+		}
 	}
+
+	shared := len(requiredPaths) > 0 &&
+		!slices.ContainsFunc(requiredPaths, func(path string) bool { return path != requiredPaths[0] })
+
+	for _, pair := range optionalArchives {
+		switch {
+		case !shared:
+			slog.DebugContext(ctx, "Skipping optional platform, its archive was not analyzed",
+				"platform", pair.Platform, "arch", pair.Arch)
+			delete(fileNames, pair.key())
+		case requiredPaths[0] != packageName:
+			paths[pair.key()] = requiredPaths[0]
+		}
+	}
+
+	return nil
 }
 
+// PlatformPair is a platform and architecture, named by GOOS and GOARCH as in fileName keys.
+type PlatformPair struct {
+	Platform string
+	Arch     string
+
+	// required pairs must have an asset for a release to be imported
+	required bool
+}
+
+// ParsePlatforms parses pairs such as linux/amd64, as given to --platforms.
+func ParsePlatforms(values []string) ([]PlatformPair, error) {
+	pairs := make([]PlatformPair, 0, len(values))
+
+	for _, value := range values {
+		platform, arch, ok := strings.Cut(strings.TrimSpace(value), "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid platform %q, expected platform/arch such as linux/amd64", value)
+		}
+
+		pair := PlatformPair{Platform: platform, Arch: arch}
+		if !slices.ContainsFunc(detectablePairs(), func(p PlatformPair) bool { return p.key() == pair.key() }) {
+			return nil, fmt.Errorf("unsupported platform %q, expected one of %s", value, joinPairs(detectablePairs()))
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
+
+func (p PlatformPair) key() string {
+	return p.Platform + "," + p.Arch
+}
+
+func (p PlatformPair) String() string {
+	return p.Platform + "/" + p.Arch
+}
+
+func joinPairs(pairs []PlatformPair) string {
+	values := make([]string, len(pairs))
+	for idx, pair := range pairs {
+		values[idx] = pair.String()
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func detectablePairs() []PlatformPair {
+	return []PlatformPair{
+		{Platform: "linux", Arch: "amd64", required: true},
+		{Platform: "linux", Arch: "arm64", required: true},
+		{Platform: "darwin", Arch: "amd64", required: true},
+		{Platform: "darwin", Arch: "arm64", required: true},
+		{Platform: "linux", Arch: "386"},
+		{Platform: "linux", Arch: "arm"},
+		{Platform: "linux", Arch: "riscv64"},
+		{Platform: "linux", Arch: "s390x"},
+		{Platform: "freebsd", Arch: "amd64"},
+		{Platform: "freebsd", Arch: "arm64"},
+	}
+}
+
+// pairs returns the pairs to detect assets for, preferring those chosen over the defaults.
+func (i *Importer) pairs() []PlatformPair {
+	if len(i.Platforms) == 0 {
+		return detectablePairs()
+	}
+
+	pairs := make([]PlatformPair, len(i.Platforms))
+	for idx, pair := range i.Platforms {
+		pair.required = true
+		pairs[idx] = pair
+	}
+
+	return pairs
+}
+
+// detectAssetName returns the name of the first asset naming both the pair's platform and architecture,
+// or an empty string when there is none.
+func detectAssetName(assets []github.Asset, pair PlatformPair) (string, error) {
+	platformDetector, err := NewPlatformPatternDetector(pair.Platform)
+	if err != nil {
+		return "", err
+	}
+
+	archDetector, err := NewArchitecturePatternDetector(pair.Arch)
+	if err != nil {
+		return "", err
+	}
+
+	for _, asset := range assets {
+		_, err := platformDetector.AnalyzeOne(asset.Name)
+		if err != nil {
+			continue
+		}
+
+		_, err = archDetector.AnalyzeOne(asset.Name)
+		if err != nil {
+			continue
+		}
+
+		return asset.Name, nil
+	}
+
+	return "", nil
+}
+
+// detectEmbeddedBinaryPaths finds the binary within each archive asset, keyed by platform and architecture.
+// Pairs whose archives cannot be analyzed are returned as failures, without affecting the other pairs.
 func detectEmbeddedBinaryPaths(
+	ctx context.Context,
 	ghClient github.Client,
 	org, repo string,
 	release *github.Release,
 	packageName string,
 	detectedAssets map[string]string,
 	versionLiteral string,
-) (*map[string]string, error) {
-	ctx := context.Background()
+) (*map[string]string, map[string]error) {
 	slog.InfoContext(ctx, "Detecting embedded binary paths", "package", packageName)
 
 	embeddedPaths := make(map[string]string)
+	failures := make(map[string]error)
 
 	for platformArch, assetName := range detectedAssets {
-		// Render the asset name template with version
-		renderedAssetName, err := renderAssetNameTemplate(assetName, versionLiteral)
-		if err != nil {
-			return nil, fmt.Errorf("failed to render asset name template %s: %w", assetName, err)
-		}
-		// Skip non-archive assets - they don't need embedded paths
-		if !isArchiveAsset(renderedAssetName) {
-			continue
-		}
-
-		slog.DebugContext(ctx, "Analyzing archive asset", "platformArch", platformArch, "asset", renderedAssetName)
-
-		// Download the asset
-		data, err := ghClient.DownloadReleaseAsset(org, repo, release.TagName, renderedAssetName)
+		binaryPath, err := detectEmbeddedBinaryPath(ctx, ghClient, org, repo, release, packageName, assetName,
+			versionLiteral)
 		if err != nil {
-			return nil, fmt.Errorf("failed to download asset %s for binary detection: %w", renderedAssetName, err)
-		}
+			failures[platformArch] = err
 
-		// List archive contents
-		files, err := listArchiveContents(renderedAssetName, bytes.NewBuffer(data))
-		if err != nil {
-			return nil, fmt.Errorf("failed to list archive contents for %s: %w", renderedAssetName, err)
-		}
-
-		// Find binary matching package name
-		binaryPath, err := findBinaryInArchive(files, packageName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find binary in asset %s: %w", renderedAssetName, err)
+			continue
 		}
 
 		// Skip if binary path is just the package name (default path)
-		if binaryPath == packageName {
+		if binaryPath == "" || binaryPath == packageName {
 			continue
 		}
 
@@ -265,11 +504,61 @@ func detectEmbeddedBinaryPaths(
 
 	// Return nil if no embedded paths were detected
 	if len(embeddedPaths) == 0 {
-		//nolint:nilnil
-		return nil, nil
+		return nil, failures
+	}
+
+	return &embeddedPaths, failures
+}
+
+// isArchiveTemplate reports whether the asset named by the template is an archive.
+func isArchiveTemplate(assetName, versionLiteral string) (bool, error) {
+	renderedAssetName, err := renderAssetNameTemplate(assetName, versionLiteral)
+	if err != nil {
+		return false, fmt.Errorf("failed to render asset name template %s: %w", assetName, err)
+	}
+
+	return isArchiveAsset(renderedAssetName), nil
+}
+
+// detectEmbeddedBinaryPath returns the path of the binary within an archive asset, or "" for other assets.
+func detectEmbeddedBinaryPath(
+	ctx context.Context,
+	ghClient github.Client,
+	org, repo string,
+	release *github.Release,
+	packageName, assetName, versionLiteral string,
+) (string, error) {
+	// Render the asset name template with version
+	renderedAssetName, err := renderAssetNameTemplate(assetName, versionLiteral)
+	if err != nil {
+		return "", fmt.Errorf("failed to render asset name template %s: %w", assetName, err)
+	}
+	// Skip non-archive assets - they don't need embedded paths
+	if !isArchiveAsset(renderedAssetName) {
+		return "", nil
+	}
+
+	slog.DebugContext(ctx, "Analyzing archive asset", "asset", renderedAssetName)
+
+	// Download the asset
+	data, err := ghClient.DownloadReleaseAsset(ctx, org, repo, release.TagName, renderedAssetName)
+	if err != nil {
+		return "", fmt.Errorf("failed to download asset %s for binary detection: %w", renderedAssetName, err)
+	}
+
+	// List archive contents
+	files, err := listArchiveContents(renderedAssetName, bytes.NewBuffer(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to list archive contents for %s: %w", renderedAssetName, err)
+	}
+
+	// Find binary matching package name
+	binaryPath, err := findBinaryInArchive(files, packageName)
+	if err != nil {
+		return "", fmt.Errorf("failed to find binary in asset %s: %w", renderedAssetName, err)
 	}
 
-	return &embeddedPaths, nil
+	return binaryPath, nil
 }
 
 func isArchiveAsset(assetName string) bool {
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"errors"
 	"os"
 	"path"
@@ -23,7 +24,7 @@ type MockGitHubClient struct {
 	downloadErrors    map[string]error
 }
 
-func (m *MockGitHubClient) GetLatestRelease(_, _ string) (*github.Release, error) {
+func (m *MockGitHubClient) GetLatestRelease(_ context.Context, _, _ string) (*github.Release, error) {
 	if m.latestRelease != nil {
 		return m.latestRelease, nil
 	}
@@ -35,11 +36,15 @@ func (m *MockGitHubClient) GetLatestRelease(_, _ string) (*github.Release, error
 	return nil, errors.New("not implemented for test")
 }
 
-func (m *MockGitHubClient) GetReleaseByTag(_, _, _ string) (*github.Release, error) {
+func (m *MockGitHubClient) GetReleaseByTag(_ context.Context, _, _, _ string) (*github.Release, error) {
 	return nil, errors.New("not implemented for test")
 }
 
-func (m *MockGitHubClient) DownloadReleaseAsset(_, _, _, asset string) ([]byte, error) {
+func (m *MockGitHubClient) ListReleases(_ context.Context, _, _ string) ([]github.Release, error) {
+	return nil, errors.New("not implemented for test")
+}
+
+func (m *MockGitHubClient) DownloadReleaseAsset(_ context.Context, _, _, _, asset string) ([]byte, error) {
 	if err, exists := m.downloadErrors[asset]; exists {
 		return nil, err
 	}
@@ -191,10 +196,10 @@ func TestDetectEmbeddedBinaryPaths(t *testing.T) {
 		downloadErrors: map[string]error{},
 	}
 
-	result, err := detectEmbeddedBinaryPaths(
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
 		mockClient, "sharkdp", "hyperfine", release, "hyperfine", detectedAssets, "1.16.1",
 	)
-	require.NoError(t, err)
+	require.Empty(t, failures)
 	require.NotNil(t, result)
 
 	expected := map[string]string{
@@ -227,10 +232,10 @@ func TestDetectEmbeddedBinaryPathsWithSubdirectory(t *testing.T) {
 		downloadErrors: map[string]error{},
 	}
 
-	result, err := detectEmbeddedBinaryPaths(
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
 		mockClient, "sharkdp", "hyperfine", release, "hyperfine", detectedAssets, "1.16.1",
 	)
-	require.NoError(t, err)
+	require.Empty(t, failures)
 	require.NotNil(t, result)
 
 	expected := map[string]string{
@@ -254,10 +259,10 @@ func TestDetectEmbeddedBinaryPathsSkipsNonArchives(t *testing.T) {
 		downloadErrors:    map[string]error{},
 	}
 
-	result, err := detectEmbeddedBinaryPaths(
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
 		mockClient, "sharkdp", "hyperfine", release, "hyperfine", detectedAssets, "1.16.1",
 	)
-	require.NoError(t, err)
+	require.Empty(t, failures)
 
 	// Should return nil since non-archive assets are skipped
 	assert.Nil(t, result)
@@ -279,14 +284,47 @@ func TestDetectEmbeddedBinaryPathsHandlesDownloadFailure(t *testing.T) {
 		},
 	}
 
-	result, err := detectEmbeddedBinaryPaths(
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
 		mockClient, "sharkdp", "hyperfine", release, "hyperfine", detectedAssets, "1.16.1",
 	)
 
-	// Should return error when download fails
-	assert.Error(t, err)
+	// Should report the pair whose download failed
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to download asset")
+	require.Contains(t, failures, "linux,amd64")
+	assert.Contains(t, failures["linux,amd64"].Error(), "failed to download asset")
+}
+
+func TestDetectEmbeddedBinaryPathsIsolatesFailures(t *testing.T) {
+	release := &github.Release{
+		TagName: "v1.16.1",
+	}
+
+	detectedAssets := map[string]string{
+		"linux,amd64": "hyperfine-v{{ .Version }}-x86_64-unknown-linux-gnu.tar.gz",
+		"linux,386":   "hyperfine-v{{ .Version }}-i686-unknown-linux-gnu.tar.gz",
+	}
+
+	mockClient := &MockGitHubClient{
+		downloadResponses: map[string][]byte{
+			"hyperfine-v1.16.1-x86_64-unknown-linux-gnu.tar.gz": createTestTarGz(map[string]string{
+				"hyperfine-v1.16.1-x86_64-unknown-linux-gnu/hyperfine": "binary content",
+			}),
+			"hyperfine-v1.16.1-i686-unknown-linux-gnu.tar.gz": []byte("not an archive"),
+		},
+		downloadErrors: map[string]error{},
+	}
+
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
+		mockClient, "sharkdp", "hyperfine", release, "hyperfine", detectedAssets, "1.16.1",
+	)
+
+	// The corrupt archive does not prevent detection for the other pair
+	require.NotNil(t, result)
+	assert.Equal(t, map[string]string{
+		"linux,amd64": "hyperfine-v{{ .Version }}-x86_64-unknown-linux-gnu/hyperfine",
+	}, *result)
+	assert.Len(t, failures, 1)
+	assert.Contains(t, failures, "linux,386")
 }
 
 func TestDetectEmbeddedBinaryPathsVersionTemplating(t *testing.T) {
@@ -318,8 +356,10 @@ func TestDetectEmbeddedBinaryPathsVersionTemplating(t *testing.T) {
 		downloadErrors: map[string]error{},
 	}
 
-	result, err := detectEmbeddedBinaryPaths(mockClient, "example", "tool", release, "tool", detectedAssets, "2.5.0")
-	require.NoError(t, err)
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
+		mockClient, "example", "tool", release, "tool", detectedAssets, "2.5.0",
+	)
+	require.Empty(t, failures)
 	require.NotNil(t, result)
 
 	expected := map[string]string{
@@ -352,10 +392,10 @@ func TestDetectEmbeddedBinaryPathsVersionTemplatingWithoutVersionInPath(t *testi
 		downloadErrors: map[string]error{},
 	}
 
-	result, err := detectEmbeddedBinaryPaths(
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
 		mockClient, "example", "simple-tool", release, "simple-tool", detectedAssets, "1.0.0",
 	)
-	require.NoError(t, err)
+	require.Empty(t, failures)
 	require.NotNil(t, result)
 
 	expected := map[string]string{
@@ -386,8 +426,10 @@ func TestDetectEmbeddedBinaryPathsVersionDetectionError(t *testing.T) {
 		downloadErrors: map[string]error{},
 	}
 
-	result, err := detectEmbeddedBinaryPaths(mockClient, "example", "tool", release, "tool", detectedAssets, "")
-	require.NoError(t, err)
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
+		mockClient, "example", "tool", release, "tool", detectedAssets, "",
+	)
+	require.Empty(t, failures)
 	require.NotNil(t, result)
 
 	// Should return literal path without templating when version detection fails
@@ -422,10 +464,10 @@ func TestDetectEmbeddedBinaryPathsWithCustomPackageName(t *testing.T) {
 
 	// Use custom package name instead of default repo name
 	customPackageName := "my-custom-name"
-	result, err := detectEmbeddedBinaryPaths(
+	result, failures := detectEmbeddedBinaryPaths(t.Context(),
 		mockClient, "example", "repo-name", release, customPackageName, detectedAssets, "1.0.0",
 	)
-	require.NoError(t, err)
+	require.Empty(t, failures)
 	require.NotNil(t, result)
 
 	expected := map[string]string{
@@ -500,7 +542,7 @@ func TestImportPackageReturnsResult(t *testing.T) {
 
 	imp := NewImporter(mockClient)
 
-	result, err := imp.ImportPackage(gCtx, "https://github.com/example/mytool", "", &bytes.Buffer{})
+	result, err := imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/mytool", "", &bytes.Buffer{})
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
@@ -547,10 +589,315 @@ func TestImportPackageWithCustomName(t *testing.T) {
 
 	imp := NewImporter(mockClient)
 
-	result, err := imp.ImportPackage(gCtx, "https://github.com/example/mytool", "my-custom-tool", &bytes.Buffer{})
+	result, err := imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/mytool", "my-custom-tool", &bytes.Buffer{})
 	require.NoError(t, err)
 	require.NotNil(t, result)
 
 	assert.Equal(t, "my-custom-tool", result.PackageName)
 	assert.Equal(t, "2.0.0", result.Version)
 }
+
+func TestImportPackageFromGitea(t *testing.T) {
+	gCtx := makeEmptyGrabContext(t)
+
+	release := &github.Release{
+		TagName: "v0.9.0",
+		Assets: []github.Asset{
+			{Name: "widget-0.9.0-linux-amd64"},
+			{Name: "widget-0.9.0-linux-arm64"},
+			{Name: "widget-0.9.0-darwin-amd64"},
+			{Name: "widget-0.9.0-darwin-arm64"},
+		},
+	}
+
+	mockClient := &MockGitHubClient{
+		latestRelease: release,
+	}
+
+	imp := NewGiteaImporter(mockClient, "forgejo.corp")
+
+	result, err := imp.ImportPackage(t.Context(), gCtx, "https://forgejo.corp/tools/widget", "", &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, "widget", result.PackageName)
+
+	saved, err := os.ReadFile(path.Join(gCtx.RepoPath, "widget.yml"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(saved), "  giteaRelease:\n    host: forgejo.corp\n    org: tools\n    repo: widget\n")
+	assert.NotContains(t, string(saved), "gitHubRelease")
+
+	// GitHub URLs are rejected by a Gitea importer
+	_, err = imp.ImportPackage(t.Context(), gCtx, "https://github.com/tools/widget", "", &bytes.Buffer{})
+	assert.ErrorContains(t, err, "URL must be from forgejo.corp, got: github.com")
+}
+
+func TestImportPackageFromGitHubEnterprise(t *testing.T) {
+	gCtx := makeEmptyGrabContext(t)
+
+	release := &github.Release{
+		TagName: "v3.1.0",
+		Assets: []github.Asset{
+			{Name: "deployer-3.1.0-linux-amd64"},
+			{Name: "deployer-3.1.0-linux-arm64"},
+			{Name: "deployer-3.1.0-darwin-amd64"},
+			{Name: "deployer-3.1.0-darwin-arm64"},
+		},
+	}
+
+	mockClient := &MockGitHubClient{
+		latestRelease: release,
+	}
+
+	imp := NewGitHubEnterpriseImporter(mockClient, "ghe.corp")
+
+	result, err := imp.ImportPackage(t.Context(), gCtx, "https://ghe.corp/platform/deployer", "", &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, "deployer", result.PackageName)
+
+	saved, err := os.ReadFile(path.Join(gCtx.RepoPath, "deployer.yml"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(saved), "  gitHubRelease:\n    host: ghe.corp\n    org: platform\n    repo: deployer\n")
+}
+
+func TestImportPackageDetectsAdditionalPlatforms(t *testing.T) {
+	gCtx := makeEmptyGrabContext(t)
+
+	release := &github.Release{
+		TagName: "v1.0.0",
+		Assets: []github.Asset{
+			{Name: "agent_1.0.0_Linux_x86_64"},
+			{Name: "agent_1.0.0_Linux_arm64"},
+			{Name: "agent_1.0.0_Linux_i386"},
+			{Name: "agent_1.0.0_Linux_armv6"},
+			{Name: "agent_1.0.0_Linux_armv7"},
+			{Name: "agent_1.0.0_Linux_riscv64"},
+			{Name: "agent_1.0.0_Linux_s390x"},
+			{Name: "agent_1.0.0_Darwin_x86_64"},
+			{Name: "agent_1.0.0_Darwin_arm64"},
+			{Name: "agent_1.0.0_FreeBSD_x86_64"},
+		},
+	}
+
+	imp := NewImporter(&MockGitHubClient{latestRelease: release})
+
+	_, err := imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/agent", "", &bytes.Buffer{})
+	require.NoError(t, err)
+
+	saved, err := os.ReadFile(path.Join(gCtx.RepoPath, "agent.yml"))
+	require.NoError(t, err)
+
+	for _, expected := range []string{
+		"linux,386: agent_{{ .Version }}_Linux_i386\n",
+		"linux,arm: agent_{{ .Version }}_Linux_armv7\n",
+		"linux,riscv64: agent_{{ .Version }}_Linux_riscv64\n",
+		"linux,s390x: agent_{{ .Version }}_Linux_s390x\n",
+		"freebsd,amd64: agent_{{ .Version }}_FreeBSD_x86_64\n",
+	} {
+		assert.Contains(t, string(saved), expected)
+	}
+
+	// Pairs without assets are left out, rather than failing the import
+	assert.NotContains(t, string(saved), "freebsd,arm64")
+}
+
+func TestDetectPackageReusesSharedEmbeddedPathForOptionalPlatforms(t *testing.T) {
+	archive := createTestTarGz(map[string]string{"agent_1.0.0/agent": "binary content"})
+
+	release := &github.Release{
+		TagName: "v1.0.0",
+		Assets: []github.Asset{
+			{Name: "agent_1.0.0_Linux_x86_64.tar.gz"},
+			{Name: "agent_1.0.0_Linux_arm64.tar.gz"},
+			{Name: "agent_1.0.0_Linux_i386.tar.gz"},
+			{Name: "agent_1.0.0_Darwin_x86_64.tar.gz"},
+			{Name: "agent_1.0.0_Darwin_arm64.tar.gz"},
+		},
+	}
+
+	// The i386 archive is not downloaded, so the mock does not serve it
+	mockClient := &MockGitHubClient{
+		downloadResponses: map[string][]byte{
+			"agent_1.0.0_Linux_x86_64.tar.gz":  archive,
+			"agent_1.0.0_Linux_arm64.tar.gz":   archive,
+			"agent_1.0.0_Darwin_x86_64.tar.gz": archive,
+			"agent_1.0.0_Darwin_arm64.tar.gz":  archive,
+		},
+	}
+
+	detected, err := detectPackage(t.Context(), mockClient, "example", "agent", release, "agent",
+		detectablePairs(), false)
+	require.NoError(t, err)
+
+	assert.Equal(t, "agent_{{ .Version }}_Linux_i386.tar.gz", detected.assets["linux,386"])
+	assert.Equal(t, map[string]string{
+		"linux,amd64":  "agent_{{ .Version }}/agent",
+		"linux,arm64":  "agent_{{ .Version }}/agent",
+		"linux,386":    "agent_{{ .Version }}/agent",
+		"darwin,amd64": "agent_{{ .Version }}/agent",
+		"darwin,arm64": "agent_{{ .Version }}/agent",
+	}, detected.embeddedBinaryPaths)
+}
+
+func TestDetectPackageSkipsOptionalPlatformsWithoutSharedEmbeddedPath(t *testing.T) {
+	release := &github.Release{
+		TagName: "v1.0.0",
+		Assets: []github.Asset{
+			{Name: "agent_1.0.0_Linux_x86_64.tar.gz"},
+			{Name: "agent_1.0.0_Linux_arm64.tar.gz"},
+			{Name: "agent_1.0.0_Linux_i386.tar.gz"},
+			{Name: "agent_1.0.0_Darwin_x86_64.tar.gz"},
+			{Name: "agent_1.0.0_Darwin_arm64.tar.gz"},
+		},
+	}
+
+	mockClient := &MockGitHubClient{
+		downloadResponses: map[string][]byte{
+			"agent_1.0.0_Linux_x86_64.tar.gz":  createTestTarGz(map[string]string{"linux_amd64/agent": "binary"}),
+			"agent_1.0.0_Linux_arm64.tar.gz":   createTestTarGz(map[string]string{"linux_arm64/agent": "binary"}),
+			"agent_1.0.0_Darwin_x86_64.tar.gz": createTestTarGz(map[string]string{"darwin_amd64/agent": "binary"}),
+			"agent_1.0.0_Darwin_arm64.tar.gz":  createTestTarGz(map[string]string{"darwin_arm64/agent": "binary"}),
+		},
+	}
+
+	detected, err := detectPackage(t.Context(), mockClient, "example", "agent", release, "agent",
+		detectablePairs(), false)
+	require.NoError(t, err)
+
+	assert.NotContains(t, detected.assets, "linux,386")
+	assert.NotContains(t, detected.embeddedBinaryPaths, "linux,386")
+	assert.Len(t, detected.embeddedBinaryPaths, 4)
+}
+
+func TestDetectPackageWritesEmbeddedPathForRequiredPlatformThatFailsAnalysis(t *testing.T) {
+	archive := createTestTarGz(map[string]string{"bin/agent": "binary content"})
+
+	release := &github.Release{
+		TagName: "v1.0.0",
+		Assets: []github.Asset{
+			{Name: "agent_1.0.0_Linux_x86_64.tar.gz"},
+			{Name: "agent_1.0.0_Linux_arm64.tar.gz"},
+			{Name: "agent_1.0.0_Darwin_x86_64.tar.gz"},
+			{Name: "agent_1.0.0_Darwin_arm64.tar.gz"},
+		},
+	}
+
+	// The darwin/arm64 archive cannot be downloaded
+	mockClient := &MockGitHubClient{
+		downloadResponses: map[string][]byte{
+			"agent_1.0.0_Linux_x86_64.tar.gz":  archive,
+			"agent_1.0.0_Linux_arm64.tar.gz":   archive,
+			"agent_1.0.0_Darwin_x86_64.tar.gz": archive,
+		},
+	}
+
+	detected, err := detectPackage(t.Context(), mockClient, "example", "agent", release, "agent",
+		detectablePairs(), false)
+	require.NoError(t, err)
+
+	// Every kept pair has an entry, as a missing one fails installs on that platform
+	assert.Equal(t, map[string]string{
+		"linux,amd64":  "bin/agent",
+		"linux,arm64":  "bin/agent",
+		"darwin,amd64": "bin/agent",
+		"darwin,arm64": "agent",
+	}, detected.embeddedBinaryPaths)
+}
+
+func TestImportPackagePartialPlatforms(t *testing.T) {
+	release := &github.Release{
+		TagName: "v2.1.0",
+		Assets: []github.Asset{
+			{Name: "daemon-2.1.0-linux-amd64"},
+			{Name: "daemon-2.1.0-linux-arm64"},
+		},
+	}
+
+	t.Run("RequiredPlatformMissing", func(t *testing.T) {
+		gCtx := makeEmptyGrabContext(t)
+		imp := NewImporter(&MockGitHubClient{latestRelease: release})
+
+		_, err := imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/daemon", "", &bytes.Buffer{})
+
+		require.EqualError(t, err, "no matching asset name found for platform darwin and architecture amd64")
+	})
+
+	t.Run("Platforms", func(t *testing.T) {
+		gCtx := makeEmptyGrabContext(t)
+		imp := NewImporter(&MockGitHubClient{latestRelease: release})
+
+		var err error
+
+		imp.Platforms, err = ParsePlatforms([]string{"linux/amd64", "linux/arm64"})
+		require.NoError(t, err)
+
+		out := bytes.Buffer{}
+		_, err = imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/daemon", "", &out)
+		require.NoError(t, err)
+
+		saved, err := os.ReadFile(path.Join(gCtx.RepoPath, "daemon.yml"))
+		require.NoError(t, err)
+
+		assert.Contains(t, string(saved), "linux,amd64: daemon-{{ .Version }}-linux-amd64\n")
+		assert.Contains(t, string(saved), "linux,arm64: daemon-{{ .Version }}-linux-arm64\n")
+		assert.NotContains(t, string(saved), "darwin")
+		assert.NotContains(t, out.String(), "No assets found")
+	})
+
+	t.Run("ChosenPlatformMissing", func(t *testing.T) {
+		gCtx := makeEmptyGrabContext(t)
+		imp := NewImporter(&MockGitHubClient{latestRelease: release})
+		imp.Platforms = []PlatformPair{{Platform: "linux", Arch: "amd64"}, {Platform: "linux", Arch: "s390x"}}
+
+		_, err := imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/daemon", "", &bytes.Buffer{})
+
+		require.EqualError(t, err, "no matching asset name found for platform linux and architecture s390x")
+	})
+
+	t.Run("BestEffort", func(t *testing.T) {
+		gCtx := makeEmptyGrabContext(t)
+		imp := NewImporter(&MockGitHubClient{latestRelease: release})
+		imp.BestEffort = true
+
+		out := bytes.Buffer{}
+		_, err := imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/daemon", "", &out)
+		require.NoError(t, err)
+
+		saved, err := os.ReadFile(path.Join(gCtx.RepoPath, "daemon.yml"))
+		require.NoError(t, err)
+
+		assert.Contains(t, string(saved), "linux,arm64: daemon-{{ .Version }}-linux-arm64\n")
+		assert.NotContains(t, string(saved), "darwin")
+		assert.Contains(t, out.String(),
+			"No assets found for darwin/amd64, darwin/arm64, so \"daemon\" will not install there\n")
+	})
+
+	t.Run("BestEffortNothingFound", func(t *testing.T) {
+		gCtx := makeEmptyGrabContext(t)
+		imp := NewImporter(&MockGitHubClient{latestRelease: &github.Release{
+			TagName: "v2.1.0",
+			Assets:  []github.Asset{{Name: "daemon-2.1.0-windows-amd64.exe"}},
+		}})
+		imp.BestEffort = true
+
+		_, err := imp.ImportPackage(t.Context(), gCtx, "https://github.com/example/daemon", "", &bytes.Buffer{})
+
+		require.ErrorContains(t, err, "no matching asset names found for linux/amd64, linux/arm64, darwin/amd64")
+	})
+}
+
+func TestParsePlatforms(t *testing.T) {
+	pairs, err := ParsePlatforms([]string{"linux/amd64", " freebsd/arm64"})
+	require.NoError(t, err)
+	assert.Equal(t, []PlatformPair{{Platform: "linux", Arch: "amd64"}, {Platform: "freebsd", Arch: "arm64"}}, pairs)
+
+	pairs, err = ParsePlatforms(nil)
+	require.NoError(t, err)
+	assert.Empty(t, pairs)
+
+	_, err = ParsePlatforms([]string{"linux-amd64"})
+	require.EqualError(t, err, `invalid platform "linux-amd64", expected platform/arch such as linux/amd64`)
+
+	_, err = ParsePlatforms([]string{"windows/amd64"})
+	require.ErrorContains(t, err, `unsupported platform "windows/amd64", expected one of linux/amd64, linux/arm64`)
+}
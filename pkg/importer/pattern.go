@@ -2,6 +2,7 @@ package importer
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -40,70 +41,68 @@ func NewReleaseNamePatternDetector() *PatternDetector {
 	}
 }
 
+// platformPatterns match the names of each platform in asset names, keyed by GOOS.
+var platformPatterns = map[string][]pattern{
+	"darwin": {
+		{Name: "darwin", Regex: regexp.MustCompile(`(?i)darwin`), Value: "darwin"},
+		{Name: "macos", Regex: regexp.MustCompile(`(?i)macos`), Value: "macos"},
+	},
+	"linux": {
+		{Name: "linux", Regex: regexp.MustCompile(`(?i)linux`), Value: "linux"},
+	},
+	"freebsd": {
+		{Name: "freebsd", Regex: regexp.MustCompile(`(?i)freebsd`), Value: "freebsd"},
+	},
+}
+
+// architecturePatterns match the names of each architecture in asset names, keyed by GOARCH.
+// Short names are matched as whole words, so that x86 does not match x86_64 and arm does not match arm64.
+var architecturePatterns = map[string][]pattern{
+	"amd64": {
+		{Name: "amd64", Regex: regexp.MustCompile(`(?i)amd64`), Value: "amd64"},
+		{Name: "x86_64", Regex: regexp.MustCompile(`(?i)x86_64`), Value: "x86_64"},
+		{Name: "x64", Regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])x64(?:[^a-z0-9]|$)`), Value: "x64"},
+	},
+	"arm64": {
+		{Name: "arm64", Regex: regexp.MustCompile(`(?i)arm64`), Value: "arm64"},
+		{Name: "aarch64", Regex: regexp.MustCompile(`(?i)aarch64`), Value: "aarch64"},
+	},
+	"386": {
+		{Name: "i386", Regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])i?386(?:[^a-z0-9]|$)`), Value: "386"},
+		{Name: "i686", Regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])i686(?:[^a-z0-9]|$)`), Value: "i686"},
+		{Name: "x86", Regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])x86(?:[^a-z0-9_]|$)`), Value: "x86"},
+	},
+	"arm": {
+		{Name: "armv7", Regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])armv7l?(?:[^a-z0-9]|$)`), Value: "armv7"},
+		{Name: "armhf", Regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])armhf(?:[^a-z0-9]|$)`), Value: "armhf"},
+		{Name: "arm", Regex: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])arm(?:[^a-z0-9]|$)`), Value: "arm"},
+	},
+	"riscv64": {
+		{Name: "riscv64", Regex: regexp.MustCompile(`(?i)riscv64`), Value: "riscv64"},
+	},
+	"s390x": {
+		{Name: "s390x", Regex: regexp.MustCompile(`(?i)s390x`), Value: "s390x"},
+	},
+}
+
 // Detecting platform.
-func NewPlatformPatternDetector(platform string) *PatternDetector {
-	switch platform {
-	case "darwin":
-		return &PatternDetector{
-			patterns: []pattern{
-				{
-					Name:  "darwin",
-					Regex: regexp.MustCompile(`(?i)darwin`),
-					Value: "darwin",
-				},
-			},
-		}
-	case "linux":
-		return &PatternDetector{
-			patterns: []pattern{
-				{
-					Name:  "linux",
-					Regex: regexp.MustCompile(`(?i)linux`),
-					Value: "linux",
-				},
-			},
-		}
-	default:
-		panic("unsupported platform: " + platform)
+func NewPlatformPatternDetector(platform string) (*PatternDetector, error) {
+	patterns, ok := platformPatterns[platform]
+	if !ok {
+		return nil, fmt.Errorf("unsupported platform %q", platform)
 	}
+
+	return &PatternDetector{patterns: patterns}, nil
 }
 
 // Detecting architecture.
-func NewArchitecturePatternDetector(architecture string) *PatternDetector {
-	switch architecture {
-	case "amd64":
-		return &PatternDetector{
-			patterns: []pattern{
-				{
-					Name:  "amd64",
-					Regex: regexp.MustCompile(`(?i)amd64`),
-					Value: "amd64",
-				},
-				{
-					Name:  "x86_64",
-					Regex: regexp.MustCompile(`(?i)x86_64`),
-					Value: "x86_64",
-				},
-			},
-		}
-	case "arm64":
-		return &PatternDetector{
-			patterns: []pattern{
-				{
-					Name:  "arm64",
-					Regex: regexp.MustCompile(`(?i)arm64`),
-					Value: "arm64",
-				},
-				{
-					Name:  "aarch64",
-					Regex: regexp.MustCompile(`(?i)aarch64`),
-					Value: "aarch64",
-				},
-			},
-		}
-	default:
-		panic("unsupported architecture: " + architecture)
+func NewArchitecturePatternDetector(architecture string) (*PatternDetector, error) {
+	patterns, ok := architecturePatterns[architecture]
+	if !ok {
+		return nil, fmt.Errorf("unsupported architecture %q", architecture)
 	}
+
+	return &PatternDetector{patterns: patterns}, nil
 }
 
 func (pd *PatternDetector) AnalyzeOne(value string) (*DetectedPattern, error) {
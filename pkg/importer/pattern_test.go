@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDetectVersionRegex(t *testing.T) {
@@ -42,6 +43,82 @@ func TestDetectVersionRegex(t *testing.T) {
 	}
 }
 
+func TestArchitecturePatternDetector(t *testing.T) {
+	tests := []struct {
+		architecture string
+		matches      []string
+		rejects      []string
+	}{
+		{
+			architecture: "amd64",
+			matches:      []string{"tool-linux-amd64", "tool_Linux_x86_64.tar.gz", "tool-linux-x64.zip"},
+			rejects:      []string{"tool-linux-386", "tool-linux-arm64"},
+		},
+		{
+			architecture: "arm64",
+			matches:      []string{"tool-linux-arm64", "tool-aarch64-unknown-linux-gnu.tar.gz"},
+			rejects:      []string{"tool-linux-armv7", "tool-linux-amd64"},
+		},
+		{
+			architecture: "386",
+			matches:      []string{"tool-linux-386.tar.gz", "tool_linux_i386", "tool-i686-unknown-linux-musl", "tool-linux-x86"},
+			rejects:      []string{"tool_Linux_x86_64.tar.gz", "tool-linux-amd64"},
+		},
+		{
+			architecture: "arm",
+			matches:      []string{"tool-linux-armv7.tar.gz", "tool-armv7l-linux", "tool_linux_armhf.deb", "tool-linux-arm"},
+			rejects:      []string{"tool-linux-arm64", "tool-linux-armv6", "tool-aarch64-linux"},
+		},
+		{
+			architecture: "riscv64",
+			matches:      []string{"tool-linux-riscv64.tar.gz"},
+			rejects:      []string{"tool-linux-amd64"},
+		},
+		{
+			architecture: "s390x",
+			matches:      []string{"tool_Linux_s390x.tar.gz"},
+			rejects:      []string{"tool-linux-amd64"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.architecture, func(t *testing.T) {
+			detector, err := NewArchitecturePatternDetector(test.architecture)
+			require.NoError(t, err)
+
+			for _, value := range test.matches {
+				_, err := detector.AnalyzeOne(value)
+				assert.NoError(t, err, "expected %q to match", value)
+			}
+
+			for _, value := range test.rejects {
+				_, err := detector.AnalyzeOne(value)
+				assert.Error(t, err, "expected %q not to match", value)
+			}
+		})
+	}
+}
+
+func TestPlatformPatternDetector(t *testing.T) {
+	detector, err := NewPlatformPatternDetector("freebsd")
+	require.NoError(t, err)
+
+	_, err = detector.AnalyzeOne("tool-freebsd-amd64.tar.gz")
+	require.NoError(t, err)
+
+	detector, err = NewPlatformPatternDetector("darwin")
+	require.NoError(t, err)
+
+	_, err = detector.AnalyzeOne("tool-macos-arm64.zip")
+	require.NoError(t, err)
+
+	_, err = NewPlatformPatternDetector("plan9")
+	require.EqualError(t, err, `unsupported platform "plan9"`)
+
+	_, err = NewArchitecturePatternDetector("mips")
+	require.EqualError(t, err, `unsupported architecture "mips"`)
+}
+
 func TestDetectVersionValue(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -9,6 +9,9 @@ import (
 
 // GitHubReleaseURL represents a parsed GitHub URL.
 type GitHubReleaseURL struct {
+	// Host is the host name the repository is served from
+	Host string
+
 	// Organization is the GitHub organization or user name
 	Organization string
 
@@ -67,7 +70,12 @@ const (
 
 // ParseGitHubReleaseURL parses a GitHub URL and extracts org/repo components.
 func ParseGitHubReleaseURL(inputURL string) (*GitHubReleaseURL, error) {
-	parsedURL, err := parseAndValidateURL(inputURL)
+	return ParseReleaseURL(inputURL, "github.com")
+}
+
+// ParseReleaseURL parses a repository URL served from host and extracts org/repo components.
+func ParseReleaseURL(inputURL, host string) (*GitHubReleaseURL, error) {
+	parsedURL, err := parseAndValidateURL(inputURL, host)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +86,7 @@ func ParseGitHubReleaseURL(inputURL string) (*GitHubReleaseURL, error) {
 	}
 
 	result := &GitHubReleaseURL{
+		Host:         parsedURL.Host,
 		Organization: organization,
 		Repository:   repository,
 		Original:     inputURL,
@@ -91,7 +100,7 @@ func ParseGitHubReleaseURL(inputURL string) (*GitHubReleaseURL, error) {
 	return result, nil
 }
 
-func parseAndValidateURL(inputURL string) (*url.URL, error) {
+func parseAndValidateURL(inputURL, host string) (*url.URL, error) {
 	parsedURL, err := url.Parse(inputURL)
 	if err != nil {
 		return nil, newParseError(inputURL, "format", fmt.Sprintf("invalid URL format: %v", err))
@@ -101,8 +110,8 @@ func parseAndValidateURL(inputURL string) (*url.URL, error) {
 		return nil, newParseError(inputURL, "scheme", "URL must use HTTPS scheme, got: "+parsedURL.Scheme)
 	}
 
-	if parsedURL.Host != "github.com" {
-		return nil, newParseError(inputURL, "host", "URL must be from github.com, got: "+parsedURL.Host)
+	if parsedURL.Host != host {
+		return nil, newParseError(inputURL, "host", fmt.Sprintf("URL must be from %s, got: %s", host, parsedURL.Host))
 	}
 
 	return parsedURL, nil
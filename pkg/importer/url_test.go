@@ -191,3 +191,25 @@ func TestGitHubReleaseURL_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReleaseURL(t *testing.T) {
+	got, err := ParseReleaseURL("https://codeberg.org/forgejo/forgejo/releases", "codeberg.org")
+	if err != nil {
+		t.Fatalf("ParseReleaseURL() unexpected error = %v", err)
+	}
+
+	if got.Host != "codeberg.org" {
+		t.Errorf("ParseReleaseURL() Host = %v, want %v", got.Host, "codeberg.org")
+	}
+
+	validateParseResult(t, got, &GitHubReleaseURL{
+		Organization: "forgejo",
+		Repository:   "forgejo",
+		Original:     "https://codeberg.org/forgejo/forgejo/releases",
+	})
+
+	_, err = ParseReleaseURL("https://github.com/forgejo/forgejo", "codeberg.org")
+	if err == nil {
+		t.Error("ParseReleaseURL() expected error for mismatched host")
+	}
+}
@@ -12,15 +12,17 @@ import (
 	"path"
 	"strings"
 
-	"github.com/noizwaves/grab/pkg/github"
+	"github.com/noizwaves/grab/pkg/progress"
 )
 
 type Installer struct {
-	GitHubClient github.Client
+	Sources *Sources
+
+	// Progress displays the progress of downloads, when set
+	Progress *progress.Reporter
 }
 
-func (i *Installer) Install(gCtx *GrabContext, packageName string, out io.Writer) error {
-	ctx := context.Background()
+func (i *Installer) Install(ctx context.Context, gCtx *GrabContext, packageName string, out io.Writer) error {
 	if packageName != "" {
 		slog.InfoContext(ctx, "Installing specific package", "package", packageName)
 	} else {
@@ -38,7 +40,7 @@ func (i *Installer) Install(gCtx *GrabContext, packageName string, out io.Writer
 	}
 
 	for _, binary := range binariesToProcess {
-		err := i.installBinary(gCtx, binary, out)
+		err := i.installBinary(ctx, gCtx, binary, out)
 		if err != nil {
 			return err
 		}
@@ -47,6 +49,77 @@ func (i *Installer) Install(gCtx *GrabContext, packageName string, out io.Writer
 	return nil
 }
 
+// InstallVersion installs a specific version of a configured package, instead of its pinned version.
+// The version is validated against the package's releases before anything is downloaded.
+func (i *Installer) InstallVersion(
+	ctx context.Context, gCtx *GrabContext, packageName, version string, out io.Writer,
+) error {
+	slog.InfoContext(ctx, "Installing specific package version", "package", packageName, "version", version)
+
+	binary := i.findBinaryByName(gCtx.Binaries, packageName)
+	if binary == nil {
+		return errors.New("package definition for " + packageName + " not found")
+	}
+
+	pinned := *binary
+	pinned.PinnedVersion = version
+
+	err := i.validateVersion(ctx, &pinned)
+	if err != nil {
+		return err
+	}
+
+	err = gCtx.EnsureBinPathExists()
+	if err != nil {
+		return fmt.Errorf("bin path needs to exist before attempting install: %w", err)
+	}
+
+	return i.installBinary(ctx, gCtx, &pinned, out)
+}
+
+// validateVersion checks that the binary's pinned version has been released.
+func (i *Installer) validateVersion(ctx context.Context, binary *Binary) error {
+	version := binary.PinnedVersion
+
+	source, err := i.Sources.SourceFor(binary)
+	if err != nil {
+		return err
+	}
+
+	if releaseSource, ok := source.(ReleaseSource); ok {
+		_, err := releaseSource.GetRelease(ctx, binary)
+		if errors.Is(err, ErrReleaseNotFound) {
+			return fmt.Errorf("version %q of %s not found in releases", version, binary.Name)
+		} else if err != nil {
+			return fmt.Errorf("error looking up version %q of %s: %w", version, binary.Name, err)
+		}
+
+		return nil
+	}
+
+	releases, err := source.ListReleases(ctx, binary)
+	if errors.Is(err, ErrListingUnsupported) {
+		slog.DebugContext(ctx, "Skipping version validation, releases cannot be listed", "package", binary.Name)
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error listing releases for %s: %w", binary.Name, err)
+	}
+
+	for _, release := range releases {
+		releaseVersion, err := extractReleaseVersion(binary, &release)
+		if err != nil {
+			continue
+		}
+
+		if releaseVersion == version {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("version %q of %s not found in releases", version, binary.Name)
+}
+
 func (i *Installer) getBinariesToProcess(gCtx *GrabContext, packageName string) ([]*Binary, error) {
 	if packageName != "" {
 		foundBinary := i.findBinaryByName(gCtx.Binaries, packageName)
@@ -70,13 +143,13 @@ func (i *Installer) findBinaryByName(binaries []*Binary, packageName string) *Bi
 	return nil
 }
 
-func (i *Installer) installBinary(gCtx *GrabContext, binary *Binary, out io.Writer) error {
+func (i *Installer) installBinary(ctx context.Context, gCtx *GrabContext, binary *Binary, out io.Writer) error {
 	destPath := path.Join(gCtx.BinPath, binary.Name)
 
 	// if destination file exists
 	_, err := os.Stat(destPath)
 	if err == nil {
-		currentVersion, err := getCurrentVersion(destPath, binary)
+		currentVersion, err := getCurrentVersion(ctx, destPath, binary)
 		if err != nil {
 			return fmt.Errorf("failed to determine current version of %q: %w", binary.Name, err)
 		}
@@ -92,12 +165,22 @@ func (i *Installer) installBinary(gCtx *GrabContext, binary *Binary, out io.Writ
 		fmt.Fprintf(out, "%s: installing %s...", binary.Name, binary.PinnedVersion)
 	}
 
-	data, err := fetchExecutable(i.GitHubClient, gCtx, binary)
+	source, err := i.Sources.SourceFor(binary)
+	if err != nil {
+		return err
+	}
+
+	task := i.Progress.Start(binary.Name)
+
+	data, err := fetchExecutable(progress.NewContext(ctx, task), source, gCtx, binary)
+
+	task.Done()
+
 	if err != nil {
 		return fmt.Errorf("error executable binary for %s: %w", binary.Name, err)
 	}
 
-	err = writeToDisk(binary, &data, destPath)
+	err = writeToDisk(ctx, binary, &data, destPath)
 	if err != nil {
 		return err
 	}
@@ -107,13 +190,12 @@ func (i *Installer) installBinary(gCtx *GrabContext, binary *Binary, out io.Writ
 	return nil
 }
 
-func fetchExecutable(ghClient github.Client, gCtx *GrabContext, binary *Binary) ([]byte, error) {
-	ctx := context.Background()
+func fetchExecutable(ctx context.Context, source Source, gCtx *GrabContext, binary *Binary) ([]byte, error) {
 	slog.InfoContext(ctx, "Downloading asset", "binary", binary.Name, "version", binary.PinnedVersion)
 
-	asset, err := binary.GetAssetFileName(gCtx.Platform, gCtx.Architecture)
+	asset, err := source.ResolveAsset(ctx, binary, gCtx.Platform, gCtx.Architecture)
 	if err != nil {
-		return nil, fmt.Errorf("error getting asset filename: %w", err)
+		return nil, fmt.Errorf("error resolving asset: %w", err)
 	}
 
 	embeddedBinaryPath, err := binary.GetEmbeddedBinaryPath(gCtx.Platform, gCtx.Architecture)
@@ -121,17 +203,15 @@ func fetchExecutable(ghClient github.Client, gCtx *GrabContext, binary *Binary)
 		return nil, fmt.Errorf("error getting embedded binary path: %w", err)
 	}
 
-	release, err := binary.GetReleaseName()
-	if err != nil {
-		return nil, fmt.Errorf("error getting asset filename: %w", err)
-	}
+	// Sources that know the asset's size up front report it for servers that omit Content-Length
+	progress.FromContext(ctx).SetTotal(asset.Size)
 
-	data, err := ghClient.DownloadReleaseAsset(binary.Org, binary.Repo, release, asset)
+	data, err := source.DownloadAsset(ctx, binary, asset)
 	if err != nil {
 		return nil, fmt.Errorf("error downloading remote file: %w", err)
 	}
 
-	return extractExecutable(embeddedBinaryPath, asset, &data)
+	return extractExecutable(embeddedBinaryPath, asset.Name, &data)
 }
 
 func extractExecutable(binary, asset string, data *[]byte) ([]byte, error) {
@@ -169,8 +249,7 @@ func extractExecutable(binary, asset string, data *[]byte) ([]byte, error) {
 	return *data, nil
 }
 
-func getCurrentVersion(destPath string, binary *Binary) (string, error) {
-	ctx := context.Background()
+func getCurrentVersion(ctx context.Context, destPath string, binary *Binary) (string, error) {
 	//nolint:gosec
 	cmd := exec.CommandContext(ctx, destPath, binary.VersionArgs...)
 
@@ -190,13 +269,13 @@ func getCurrentVersion(destPath string, binary *Binary) (string, error) {
 // Write the executable to disk as atomically as possible.
 // First, it writes to a temporary file in the destination directory,
 // then it moves the temporary file to the destination path.
-func writeToDisk(binary *Binary, data *[]byte, destPath string) error {
+// The temporary file is removed if ctx is cancelled before the move.
+func writeToDisk(ctx context.Context, binary *Binary, data *[]byte, destPath string) error {
 	// Use dest instead of /tmp for temporary file writing; avoids the
 	// "invalid cross-device link" error when /tmp is on a different device
 	// i.e. memory mounted
 	destDir := path.Dir(destPath)
 	tempPath := path.Join(destDir, ".grab-temp-"+binary.Name)
-	ctx := context.Background()
 	slog.DebugContext(ctx, "Writing to temporary executable", "binary", binary.Name, "tempPath", tempPath)
 
 	// Ensure temp path is clear
@@ -211,8 +290,13 @@ func writeToDisk(binary *Binary, data *[]byte, destPath string) error {
 		return fmt.Errorf("error writing executable to temp location: %w", err)
 	}
 
-	// Best-effort clean up if rename fails
-	defer tryRemoveFromFilesystem(tempPath)
+	// Best-effort clean up if rename fails, or is abandoned
+	defer tryRemoveFromFilesystem(ctx, tempPath)
+
+	err = ctx.Err()
+	if err != nil {
+		return fmt.Errorf("error installing executable: %w", err)
+	}
 
 	err = os.Rename(tempPath, destPath)
 	if err != nil {
@@ -223,12 +307,11 @@ func writeToDisk(binary *Binary, data *[]byte, destPath string) error {
 }
 
 // Best effort to remove a file or directory from filesystem, and warn on an error.
-func tryRemoveFromFilesystem(path string) {
+func tryRemoveFromFilesystem(ctx context.Context, path string) {
 	_, err := os.Stat(path)
 	if err == nil {
 		err := os.Remove(path)
 		if err != nil {
-			ctx := context.Background()
 			slog.WarnContext(ctx, "Failed to remove file", "path", path)
 		}
 	}
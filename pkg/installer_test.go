@@ -2,13 +2,22 @@ package pkg
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/noizwaves/grab/pkg/github"
 	"github.com/noizwaves/grab/pkg/internal/asserth"
 	"github.com/noizwaves/grab/pkg/internal/githubh"
 	"github.com/noizwaves/grab/pkg/internal/osh"
+	"github.com/noizwaves/grab/pkg/progress"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Simple test case that installs one package into an empty bin directory.
@@ -22,13 +31,15 @@ func TestInstall(t *testing.T) {
 	}
 
 	installer := Installer{
-		GitHubClient: &githubh.MockGitHubClient{
-			AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+			},
 		},
 	}
 
 	out := bytes.Buffer{}
-	err = installer.Install(gCtx, "", &out)
+	err = installer.Install(t.Context(), gCtx, "", &out)
 
 	assert.NoError(t, err)
 	assert.Contains(t, out.String(), "bar: installing 1.0.0... Done!")
@@ -39,6 +50,66 @@ func TestInstall(t *testing.T) {
 	asserth.CommandStdoutContains(t, barPath, "1.0.0")
 }
 
+// Test case that installs with download progress reported as plain text, as in CI logs.
+func TestInstall_Progress(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+	binDir := t.TempDir()
+
+	gCtx, err := NewGrabContext(configDir, binDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := bytes.Buffer{}
+	reporter := progress.NewReporter(&out, false)
+
+	installer := Installer{
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+			},
+		},
+		Progress: reporter,
+	}
+
+	err = installer.Install(t.Context(), gCtx, "", reporter)
+	reporter.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bar: installing 1.0.0... Done!\n", out.String())
+	assert.FileExists(t, filepath.Join(binDir, "bar"))
+}
+
+// Test case that cancels an install, which must not leave a temporary file or partial binary behind.
+func TestInstall_Cancelled(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+	binDir := t.TempDir()
+
+	gCtx, err := NewGrabContext(configDir, binDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installer := Installer{
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err = installer.Install(ctx, gCtx, "", &bytes.Buffer{})
+
+	assert.ErrorIs(t, err, context.Canceled)
+
+	entries, err := os.ReadDir(binDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
 // Test case that installs only "bar" from a context with multiple packages,
 // and asserts that "baz" is not included in the output.
 func TestInstall_SelectivePackage(t *testing.T) {
@@ -51,13 +122,15 @@ func TestInstall_SelectivePackage(t *testing.T) {
 	}
 
 	installer := Installer{
-		GitHubClient: &githubh.MockGitHubClient{
-			AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+			},
 		},
 	}
 
 	out := bytes.Buffer{}
-	err = installer.Install(gCtx, "bar", &out)
+	err = installer.Install(t.Context(), gCtx, "bar", &out)
 
 	assert.NoError(t, err)
 	assert.Contains(t, out.String(), "bar: installing 1.0.0... Done!")
@@ -83,13 +156,15 @@ func TestInstall_SelectivePackage_PackageNotFound(t *testing.T) {
 	}
 
 	installer := Installer{
-		GitHubClient: &githubh.MockGitHubClient{
-			AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+			},
 		},
 	}
 
 	out := bytes.Buffer{}
-	err = installer.Install(gCtx, "nonexistent", &out)
+	err = installer.Install(t.Context(), gCtx, "nonexistent", &out)
 
 	assert.Error(t, err)
 	assert.Equal(t, "package definition for nonexistent not found", err.Error())
@@ -98,3 +173,116 @@ func TestInstall_SelectivePackage_PackageNotFound(t *testing.T) {
 	nonexistentPath := filepath.Join(binDir, "nonexistent")
 	assert.NoFileExists(t, nonexistentPath)
 }
+
+// Test case that installs a version other than the pinned one.
+func TestInstallVersion(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+	binDir := t.TempDir()
+
+	gCtx, err := NewGrabContext(configDir, binDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockClient := &githubh.MockGitHubClient{
+		AssetData: []byte("#!/usr/bin/env bash\necho '0.9.0'"),
+		Release:   &github.Release{Name: "0.9.0", TagName: "0.9.0", Assets: []github.Asset{{Name: "bin"}}},
+	}
+
+	installer := Installer{
+		Sources: &Sources{
+			GitHubClient: mockClient,
+		},
+	}
+
+	out := bytes.Buffer{}
+	err = installer.InstallVersion(t.Context(), gCtx, "bar", "0.9.0", &out)
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "bar: installing 0.9.0... Done!")
+	assert.Contains(t, mockClient.GetReleaseByTagCalls, githubh.GetReleaseByTagCall{Org: "foo", Repo: "bar", Tag: "0.9.0"})
+	assert.Empty(t, mockClient.ListReleasesCalls)
+
+	barPath := filepath.Join(binDir, "bar")
+	asserth.CommandStdoutContains(t, barPath, "0.9.0")
+
+	// The pinned version is left untouched
+	assert.Equal(t, "1.0.0", gCtx.Config.Packages["bar"])
+}
+
+// Test case that installs a version older than the first page of releases.
+func TestInstallVersion_OlderThanFirstPage(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+	binDir := t.TempDir()
+
+	gCtx, err := NewGrabContext(configDir, binDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/repos/foo/bar/releases":
+			// The first page holds newer releases only
+			releases := make([]github.Release, 100)
+			for i := range releases {
+				releases[i] = github.Release{Name: fmt.Sprintf("2.0.%d", i), TagName: fmt.Sprintf("2.0.%d", i)}
+			}
+
+			_ = json.NewEncoder(responseWriter).Encode(releases)
+		case "/repos/foo/bar/releases/tags/0.9.0":
+			_ = json.NewEncoder(responseWriter).Encode(github.Release{
+				Name: "0.9.0", TagName: "0.9.0", Assets: []github.Asset{{Name: "bin"}},
+			})
+		case "/foo/bar/releases/download/0.9.0/bin":
+			_, _ = responseWriter.Write([]byte("#!/usr/bin/env bash\necho '0.9.0'"))
+		default:
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := github.DefaultClientConfig()
+	config.BaseURL = server.URL
+	config.GraphQLURL = github.GraphQLURL(server.URL)
+	config.DownloadBaseURL = server.URL
+	config.AssetsAPI = false
+
+	installer := Installer{
+		Sources: &Sources{
+			GitHubClient: github.NewClientWithConfig(config),
+		},
+	}
+
+	out := bytes.Buffer{}
+	err = installer.InstallVersion(t.Context(), gCtx, "bar", "0.9.0", &out)
+
+	require.NoError(t, err)
+	asserth.CommandStdoutContains(t, filepath.Join(binDir, "bar"), "0.9.0")
+}
+
+// Test case that rejects a version that has not been released, before downloading.
+func TestInstallVersion_VersionNotFound(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+	binDir := t.TempDir()
+
+	gCtx, err := NewGrabContext(configDir, binDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installer := Installer{
+		Sources: &Sources{
+			GitHubClient: &releaseNotFoundClient{MockGitHubClient: githubh.MockGitHubClient{
+				AssetData: []byte("#!/usr/bin/env bash\necho '1.0.0'"),
+			}},
+		},
+	}
+
+	out := bytes.Buffer{}
+	err = installer.InstallVersion(t.Context(), gCtx, "bar", "0.1.0", &out)
+
+	assert.EqualError(t, err, `version "0.1.0" of bar not found in releases`)
+	assert.Empty(t, out.String())
+	assert.NoFileExists(t, filepath.Join(binDir, "bar"))
+}
@@ -1,6 +1,7 @@
 package githubh
 
 import (
+	"context"
 	"errors"
 
 	"github.com/noizwaves/grab/pkg/github"
@@ -9,10 +10,16 @@ import (
 type MockGitHubClient struct {
 	AssetData []byte
 	Release   *github.Release
+	Releases  []github.Release
+
+	// AssetError is returned by DownloadReleaseAsset, when set
+	AssetError error
 
 	// Call tracking
-	GetLatestReleaseCalls []GetLatestReleaseCall
-	GetReleaseByTagCalls  []GetReleaseByTagCall
+	GetLatestReleaseCalls         []GetLatestReleaseCall
+	GetReleaseByTagCalls          []GetReleaseByTagCall
+	ListReleasesCalls             []ListReleasesCall
+	DownloadReleaseAssetByIDCalls []int64
 }
 
 type GetLatestReleaseCall struct {
@@ -20,13 +27,22 @@ type GetLatestReleaseCall struct {
 	Repo string
 }
 
+type ListReleasesCall struct {
+	Org  string
+	Repo string
+}
+
 type GetReleaseByTagCall struct {
 	Org  string
 	Repo string
 	Tag  string
 }
 
-func (m *MockGitHubClient) DownloadReleaseAsset(_, _, _, _ string) ([]byte, error) {
+func (m *MockGitHubClient) DownloadReleaseAsset(_ context.Context, _, _, _, _ string) ([]byte, error) {
+	if m.AssetError != nil {
+		return nil, m.AssetError
+	}
+
 	if len(m.AssetData) == 0 {
 		return nil, errors.New("not implemented")
 	}
@@ -34,7 +50,15 @@ func (m *MockGitHubClient) DownloadReleaseAsset(_, _, _, _ string) ([]byte, erro
 	return m.AssetData, nil
 }
 
-func (m *MockGitHubClient) GetLatestRelease(org, repo string) (*github.Release, error) {
+func (m *MockGitHubClient) DownloadReleaseAssetByID(
+	ctx context.Context, org, repo, releaseName, assetName string, id int64,
+) ([]byte, error) {
+	m.DownloadReleaseAssetByIDCalls = append(m.DownloadReleaseAssetByIDCalls, id)
+
+	return m.DownloadReleaseAsset(ctx, org, repo, releaseName, assetName)
+}
+
+func (m *MockGitHubClient) GetLatestRelease(_ context.Context, org, repo string) (*github.Release, error) {
 	// Track the call
 	m.GetLatestReleaseCalls = append(m.GetLatestReleaseCalls, GetLatestReleaseCall{
 		Org:  org,
@@ -48,7 +72,7 @@ func (m *MockGitHubClient) GetLatestRelease(org, repo string) (*github.Release,
 	return m.Release, nil
 }
 
-func (m *MockGitHubClient) GetReleaseByTag(org, repo, tag string) (*github.Release, error) {
+func (m *MockGitHubClient) GetReleaseByTag(_ context.Context, org, repo, tag string) (*github.Release, error) {
 	// Track the call
 	m.GetReleaseByTagCalls = append(m.GetReleaseByTagCalls, GetReleaseByTagCall{
 		Org:  org,
@@ -62,3 +86,45 @@ func (m *MockGitHubClient) GetReleaseByTag(org, repo, tag string) (*github.Relea
 
 	return m.Release, nil
 }
+
+func (m *MockGitHubClient) ListReleases(_ context.Context, org, repo string) ([]github.Release, error) {
+	// Track the call
+	m.ListReleasesCalls = append(m.ListReleasesCalls, ListReleasesCall{
+		Org:  org,
+		Repo: repo,
+	})
+
+	if m.Releases == nil {
+		return nil, errors.New("not implemented")
+	}
+
+	return m.Releases, nil
+}
+
+// MockBatchGitHubClient additionally supports batch lookups of latest releases.
+type MockBatchGitHubClient struct {
+	MockGitHubClient
+
+	// LatestReleases are keyed by "owner/name"
+	LatestReleases map[string]*github.Release
+
+	// Call tracking
+	GetLatestReleasesCalls [][]github.Repository
+}
+
+func (m *MockBatchGitHubClient) GetLatestReleases(
+	_ context.Context, repos []github.Repository,
+) (map[github.Repository]*github.Release, error) {
+	// Track the call
+	m.GetLatestReleasesCalls = append(m.GetLatestReleasesCalls, repos)
+
+	output := make(map[github.Repository]*github.Release)
+
+	for _, repo := range repos {
+		if release, ok := m.LatestReleases[repo.Owner+"/"+repo.Name]; ok {
+			output[repo] = release
+		}
+	}
+
+	return output, nil
+}
@@ -3,10 +3,12 @@ package pkg
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"regexp"
 	"text/template"
+	"time"
 )
 
 type Binary struct {
@@ -14,8 +16,12 @@ type Binary struct {
 	PinnedVersion string
 
 	// source
-	Org  string
-	Repo string
+	SourceKind string
+	Host       string
+	Org        string
+	Repo       string
+	Project    string
+	Path       string
 
 	// Release Name template
 	releaseName  string
@@ -27,36 +33,166 @@ type Binary struct {
 	// (platform,arch) -> embedded binary path template
 	embeddedBinaryPath map[string]string
 
+	// (platform,arch) -> checksum URL template
+	checksumURL map[string]string
+
+	// where the latest version is published, for sources without a releases API
+	LatestVersionURL      string
+	LatestVersionJSONPath string
+
 	// program related fields
 	VersionArgs  []string
 	VersionRegex *regexp.Regexp
+
+	// minimum age of a release before it is adopted
+	MinReleaseAge time.Duration
 }
 
 func NewBinary(name, version string, config ConfigPackage) (*Binary, error) {
+	sourceKind, err := config.Spec.SourceKind()
+	if err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+
 	versionRegex, err := regexp.Compile(config.Spec.Program.VersionRegex)
 	if err != nil {
 		return nil, fmt.Errorf("version regex does not compile: %w", err)
 	}
 
-	releaseRegex, err := regexp.Compile(config.Spec.GitHubRelease.VersionRegex)
+	minReleaseAge, err := parseMinReleaseAge(config.Spec.MinReleaseAge)
 	if err != nil {
-		return nil, fmt.Errorf("release regex does not compile: %w", err)
+		return nil, err
 	}
 
-	return &Binary{
+	binary := &Binary{
 		Name:          name,
 		PinnedVersion: version,
-		// package
-		Org:                config.Spec.GitHubRelease.Org,
-		Repo:               config.Spec.GitHubRelease.Repo,
-		releaseName:        config.Spec.GitHubRelease.Name,
-		ReleaseRegex:       releaseRegex,
-		fileName:           config.Spec.GitHubRelease.FileName,
-		embeddedBinaryPath: config.Spec.GitHubRelease.EmbeddedBinaryPath,
+		SourceKind:    sourceKind,
 		// program
 		VersionArgs:  config.Spec.Program.VersionArgs,
 		VersionRegex: versionRegex,
-	}, nil
+		// policy
+		MinReleaseAge: minReleaseAge,
+	}
+
+	switch sourceKind {
+	case SourceKindGitHub:
+		err = binary.configureGitHubRelease(config.Spec.GitHubRelease)
+	case SourceKindGitLab:
+		err = binary.configureGitLabRelease(config.Spec.GitLabRelease)
+	case SourceKindGitea:
+		err = binary.configureGiteaRelease(config.Spec.GiteaRelease)
+	case SourceKindHTTP:
+		err = binary.configureHTTP(config.Spec.HTTP)
+	case SourceKindOCI:
+		err = binary.configureOCI(config.Spec.OCI)
+	case SourceKindFile:
+		err = binary.configureFile(config.Spec.File)
+	default:
+		err = fmt.Errorf("unsupported source kind %q", sourceKind)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return binary, nil
+}
+
+func (b *Binary) configureGitHubRelease(config *ConfigGitHubRelease) error {
+	b.Host = config.Host
+	b.Org = config.Org
+	b.Repo = config.Repo
+
+	return b.configureReleaseTemplates(config.Name, config.VersionRegex, config.FileName, config.EmbeddedBinaryPath)
+}
+
+func (b *Binary) configureGitLabRelease(config *ConfigGitLabRelease) error {
+	b.Host = config.Host
+	b.Project = config.Project
+
+	return b.configureReleaseTemplates(config.Name, config.VersionRegex, config.FileName, config.EmbeddedBinaryPath)
+}
+
+func (b *Binary) configureGiteaRelease(config *ConfigGiteaRelease) error {
+	if config.Host == "" {
+		return errors.New("gitea release host is required")
+	}
+
+	b.Host = config.Host
+	b.Org = config.Org
+	b.Repo = config.Repo
+
+	return b.configureReleaseTemplates(config.Name, config.VersionRegex, config.FileName, config.EmbeddedBinaryPath)
+}
+
+func (b *Binary) configureHTTP(config *ConfigHTTP) error {
+	b.checksumURL = config.ChecksumURL
+
+	if config.LatestVersion != nil {
+		b.LatestVersionURL = config.LatestVersion.URL
+		b.LatestVersionJSONPath = config.LatestVersion.JSONPath
+	}
+
+	// Download URLs take the place of asset file names, and versions are not published as named releases
+	return b.configureReleaseTemplates("{{ .Version }}", config.VersionRegex, config.URL, config.EmbeddedBinaryPath)
+}
+
+func (b *Binary) configureOCI(config *ConfigOCI) error {
+	if config.Registry == "" {
+		return errors.New("oci registry is required")
+	}
+
+	b.Host = config.Registry
+	b.Repo = config.Repository
+
+	// Tags take the place of release names, and file names select layers by title
+	return b.configureReleaseTemplates(config.Tag, config.VersionRegex, config.FileName, config.EmbeddedBinaryPath)
+}
+
+func (b *Binary) configureFile(config *ConfigFile) error {
+	if config.Path == "" {
+		return errors.New("file path is required")
+	}
+
+	b.Path = config.Path
+	b.Org = config.Org
+	b.Repo = config.Repo
+
+	return b.configureReleaseTemplates(config.Name, config.VersionRegex, config.FileName, config.EmbeddedBinaryPath)
+}
+
+func (b *Binary) configureReleaseTemplates(
+	releaseName, versionRegex string, fileName, embeddedBinaryPath map[string]string,
+) error {
+	releaseRegex, err := regexp.Compile(versionRegex)
+	if err != nil {
+		return fmt.Errorf("release regex does not compile: %w", err)
+	}
+
+	b.releaseName = releaseName
+	b.ReleaseRegex = releaseRegex
+	b.fileName = fileName
+	b.embeddedBinaryPath = embeddedBinaryPath
+
+	return nil
+}
+
+func parseMinReleaseAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("min release age is not a valid duration: %w", err)
+	}
+
+	if duration < 0 {
+		return 0, fmt.Errorf("min release age cannot be negative, got %q", value)
+	}
+
+	return duration, nil
 }
 
 func (b *Binary) GetAssetFileName(platform, arch string) (string, error) {
@@ -115,6 +251,32 @@ func (b *Binary) GetEmbeddedBinaryPath(platform, arch string) (string, error) {
 	return output.String(), nil
 }
 
+// GetChecksumURL returns the URL of the asset's checksum, or an empty string when none is configured.
+func (b *Binary) GetChecksumURL(platform, arch string) (string, error) {
+	key := platform + "," + arch
+
+	checksumURLTmplStr, ok := b.checksumURL[key]
+	if !ok {
+		return "", nil
+	}
+
+	tmpl, err := template.New("checksumURL:" + b.Name + "," + key).Parse(checksumURLTmplStr)
+	if err != nil {
+		return "", fmt.Errorf("error parsing checksum URL template: %w", err)
+	}
+
+	vm := newURLViewModel(b)
+
+	var output bytes.Buffer
+
+	err = tmpl.Execute(&output, vm)
+	if err != nil {
+		return "", fmt.Errorf("error rendering checksum URL template: %w", err)
+	}
+
+	return output.String(), nil
+}
+
 func (b *Binary) GetReleaseName() (string, error) {
 	tmpl, err := template.New("releaseName:" + b.Name).Parse(b.releaseName)
 	if err != nil {
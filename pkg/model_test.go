@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -198,3 +199,31 @@ func TestBinaryShouldReplace(t *testing.T) {
 		assert.False(t, base.ShouldReplace("1.2.3"))
 	})
 }
+
+func TestParseMinReleaseAge(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		result, err := parseMinReleaseAge("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), result)
+	})
+
+	t.Run("Hours", func(t *testing.T) {
+		result, err := parseMinReleaseAge("72h")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 72*time.Hour, result)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := parseMinReleaseAge("3 days")
+
+		assert.ErrorContains(t, err, "min release age is not a valid duration")
+	})
+
+	t.Run("Negative", func(t *testing.T) {
+		_, err := parseMinReleaseAge("-1h")
+
+		assert.ErrorContains(t, err, "min release age cannot be negative")
+	})
+}
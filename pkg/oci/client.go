@@ -0,0 +1,247 @@
+// Package oci provides a client for the distribution API of OCI registries, such as ghcr.io.
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/noizwaves/grab/pkg/progress"
+)
+
+// tagsPerPage is the number of tags requested from a registry.
+const tagsPerPage = 1000
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func parseError(resp *http.Response, data []byte) error {
+	var output errorBody
+
+	err := json.Unmarshal(data, &output)
+	if err != nil || len(output.Errors) == 0 {
+		return errors.New("unexpected status from registry: " + resp.Status)
+	}
+
+	return fmt.Errorf("%s", output.Errors[0].Message)
+}
+
+type Client interface {
+	ListTags(ctx context.Context, repository string) ([]string, error)
+	GetManifest(ctx context.Context, repository, reference string) (*Manifest, error)
+	GetBlob(ctx context.Context, repository, digest string) ([]byte, error)
+}
+
+type ClientImpl struct {
+	baseURL    string
+	httpClient *http.Client
+
+	// token is the bearer token issued by the registry's token service, once challenged
+	token string
+}
+
+// NewClient creates a client for the registry at host (e.g. ghcr.io), making requests with httpClient.
+func NewClient(host string, httpClient *http.Client) *ClientImpl {
+	return &ClientImpl{
+		baseURL:    "https://" + host,
+		httpClient: httpClient,
+	}
+}
+
+func NewClientWithBaseURL(baseURL string) *ClientImpl {
+	return &ClientImpl{
+		baseURL:    baseURL,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// ListTags returns the tags of a repository.
+// Only the first page of results is requested.
+func (c *ClientImpl) ListTags(ctx context.Context, repository string) ([]string, error) {
+	url := fmt.Sprintf("%s/v2/%s/tags/list?n=%d", c.baseURL, repository, tagsPerPage)
+
+	data, _, err := c.get(ctx, url, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var output tagList
+
+	err = json.Unmarshal(data, &output)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response as JSON: %w", err)
+	}
+
+	return output.Tags, nil
+}
+
+// GetManifest returns the manifest or index referenced by a tag or digest.
+func (c *ClientImpl) GetManifest(ctx context.Context, repository, reference string) (*Manifest, error) {
+	url := fmt.Sprintf("%s/v2/%s/manifests/%s", c.baseURL, repository, reference)
+
+	accept := strings.Join([]string{
+		MediaTypeImageIndex,
+		MediaTypeImageManifest,
+		MediaTypeDockerManifestList,
+		MediaTypeDockerManifest,
+	}, ", ")
+
+	data, header, err := c.get(ctx, url, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	var output Manifest
+
+	err = json.Unmarshal(data, &output)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response as JSON: %w", err)
+	}
+
+	// The media type is optional in the document itself
+	if output.MediaType == "" {
+		output.MediaType = header.Get("Content-Type")
+	}
+
+	return &output, nil
+}
+
+// GetBlob downloads the content with digest, such as a layer.
+func (c *ClientImpl) GetBlob(ctx context.Context, repository, digest string) ([]byte, error) {
+	url := fmt.Sprintf("%s/v2/%s/blobs/%s", c.baseURL, repository, digest)
+
+	slog.DebugContext(ctx, "Downloading blob from registry", "url", url)
+
+	data, _, err := c.get(ctx, url, "application/octet-stream")
+
+	return data, err
+}
+
+// get performs a GET request against the registry, authenticating when the registry challenges for a token.
+func (c *ClientImpl) get(ctx context.Context, url, accept string) ([]byte, http.Header, error) {
+	resp, data, err := c.do(ctx, url, accept)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		err = c.authenticate(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error authenticating with registry: %w", err)
+		}
+
+		resp, data, err = c.do(ctx, url, accept)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, parseError(resp, data)
+	}
+
+	return data, resp.Header, nil
+}
+
+func (c *ClientImpl) do(ctx context.Context, url, accept string) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating GET request: %w", err)
+	}
+
+	req.Header.Add("Accept", accept)
+
+	if c.token != "" {
+		req.Header.Add("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+
+	// Blobs report their download progress, unlike manifests and tokens
+	if accept == "application/octet-stream" && resp.StatusCode == http.StatusOK {
+		body = progress.NewReader(ctx, resp.Body, resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return resp, data, nil
+}
+
+// authenticate requests a bearer token from the token service named in a challenge.
+// Registries issue anonymous tokens for public repositories; credentials are read from
+// OCI_USERNAME and OCI_PASSWORD for private ones.
+func (c *ClientImpl) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	values := url.Values{}
+
+	realm := ""
+
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(params, -1) {
+		if match[1] == "realm" {
+			realm = match[2]
+		} else {
+			values.Set(match[1], match[2])
+		}
+	}
+
+	if realm == "" {
+		return fmt.Errorf("authentication challenge %q has no realm", challenge)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+values.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating token request: %w", err)
+	}
+
+	if username := os.Getenv("OCI_USERNAME"); username != "" {
+		req.SetBasicAuth(username, os.Getenv("OCI_PASSWORD"))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("unexpected status requesting token: " + resp.Status)
+	}
+
+	var output tokenResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&output)
+	if err != nil {
+		return fmt.Errorf("error parsing token response as JSON: %w", err)
+	}
+
+	c.token = output.Token
+	if c.token == "" {
+		c.token = output.AccessToken
+	}
+
+	if c.token == "" {
+		return errors.New("token service returned no token")
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package oci
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetManifest_TokenChallenge(t *testing.T) {
+	mockIndex := Manifest{
+		MediaType: MediaTypeImageIndex,
+		Manifests: []Descriptor{
+			{
+				MediaType: MediaTypeImageManifest,
+				Digest:    "sha256:abc",
+				Platform:  &Platform{OS: "linux", Architecture: "amd64"},
+			},
+		},
+	}
+
+	var server *httptest.Server
+
+	// Create mock registry
+	server = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/token":
+			if request.URL.Query().Get("scope") != "repository:org/tool:pull" {
+				t.Errorf("Expected scope 'repository:org/tool:pull', got '%s'", request.URL.Query().Get("scope"))
+			}
+
+			_ = json.NewEncoder(responseWriter).Encode(tokenResponse{Token: "anonymous"})
+		case "/v2/org/tool/manifests/v1.0.0":
+			if request.Header.Get("Authorization") != "Bearer anonymous" {
+				responseWriter.Header().Set("WWW-Authenticate",
+					`Bearer realm="`+server.URL+`/token",service="registry",scope="repository:org/tool:pull"`)
+				responseWriter.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			responseWriter.Header().Set("Content-Type", MediaTypeImageIndex)
+			_ = json.NewEncoder(responseWriter).Encode(mockIndex)
+		default:
+			t.Errorf("Unexpected request path '%s'", request.URL.Path)
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock registry
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.GetManifest(t.Context(), "org/tool", "v1.0.0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.IsIndex() {
+		t.Errorf("Expected an image index, got media type '%s'", result.MediaType)
+	}
+
+	if len(result.Manifests) != 1 || result.Manifests[0].Digest != "sha256:abc" {
+		t.Errorf("Expected one manifest with digest 'sha256:abc', got %v", result.Manifests)
+	}
+}
+
+func TestGetManifest_NotFound(t *testing.T) {
+	// Create mock registry
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.WriteHeader(http.StatusNotFound)
+		_, _ = responseWriter.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock registry
+	client := NewClientWithBaseURL(server.URL)
+
+	_, err := client.GetManifest(t.Context(), "org/tool", "v9.9.9")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if err.Error() != "manifest unknown" {
+		t.Errorf("Expected error message 'manifest unknown', got '%s'", err.Error())
+	}
+}
+
+func TestListTags_Success(t *testing.T) {
+	// Create mock registry
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/v2/org/tool/tags/list" {
+			t.Errorf("Expected path '/v2/org/tool/tags/list', got '%s'", request.URL.Path)
+		}
+
+		_ = json.NewEncoder(responseWriter).Encode(tagList{Name: "org/tool", Tags: []string{"v1.0.0", "latest"}})
+	}))
+	defer server.Close()
+
+	// Create client pointing to mock registry
+	client := NewClientWithBaseURL(server.URL)
+
+	result, err := client.ListTags(t.Context(), "org/tool")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result) != 2 || result[0] != "v1.0.0" {
+		t.Errorf("Expected tags [v1.0.0 latest], got %v", result)
+	}
+}
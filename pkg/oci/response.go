@@ -0,0 +1,73 @@
+package oci
+
+import "time"
+
+const (
+	MediaTypeImageIndex    = "application/vnd.oci.image.index.v1+json"
+	MediaTypeImageManifest = "application/vnd.oci.image.manifest.v1+json"
+
+	// Docker media types are served by registries for images pushed with Docker tooling
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+
+	// Image configs record when an image was created
+	MediaTypeImageConfig       = "application/vnd.oci.image.config.v1+json"
+	MediaTypeDockerImageConfig = "application/vnd.docker.container.image.v1+json"
+
+	// AnnotationTitle holds the file name of a layer, as set by ORAS.
+	AnnotationTitle = "org.opencontainers.image.title"
+
+	// AnnotationCreated holds the RFC 3339 creation time of a manifest, as set by ORAS.
+	AnnotationCreated = "org.opencontainers.image.created"
+)
+
+// Manifest is either an image manifest, which lists layers, or an image index, which lists per-platform manifests.
+type Manifest struct {
+	MediaType   string            `json:"mediaType"`
+	Config      *Descriptor       `json:"config,omitempty"`
+	Manifests   []Descriptor      `json:"manifests,omitempty"`
+	Layers      []Descriptor      `json:"layers,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// IsIndex reports whether the manifest refers to other manifests rather than layers.
+func (m *Manifest) IsIndex() bool {
+	return m.MediaType == MediaTypeImageIndex || m.MediaType == MediaTypeDockerManifestList
+}
+
+// Descriptor refers to content stored in a registry by its digest.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Platform    *Platform         `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ImageConfig is the subset of an image config describing its origin.
+type ImageConfig struct {
+	Created time.Time `json:"created"`
+}
+
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+type tagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"` //nolint:tagliatelle
+}
+
+type errorBody struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
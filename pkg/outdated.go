@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+const (
+	// StatusCurrent means the pinned version is the latest release.
+	StatusCurrent = "current"
+	// StatusOutdated means a newer release is available.
+	StatusOutdated = "outdated"
+	// StatusPending means a newer release exists but is younger than the minimum release age.
+	StatusPending = "pending"
+)
+
+const (
+	ReportFormatText     = "text"
+	ReportFormatJSON     = "json"
+	ReportFormatMarkdown = "markdown"
+)
+
+// reportFormats are the formats supported by WriteStatusReport.
+var reportFormats = []string{ReportFormatText, ReportFormatJSON, ReportFormatMarkdown}
+
+// PackageStatus describes how a configured package compares to its latest release.
+type PackageStatus struct {
+	Name             string `json:"name"`
+	CurrentVersion   string `json:"currentVersion"`
+	LatestVersion    string `json:"latestVersion"`
+	URL              string `json:"url"`
+	Status           string `json:"status"`
+	AvailableInHours int    `json:"availableInHours,omitempty"`
+}
+
+// CountOutdated returns the number of packages with an adoptable newer release.
+func CountOutdated(statuses []PackageStatus) int {
+	count := 0
+
+	for _, status := range statuses {
+		if status.Status == StatusOutdated {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ValidateReportFormat checks that format is supported, so that it can be rejected before any work is done.
+func ValidateReportFormat(format string) error {
+	if !slices.Contains(reportFormats, format) {
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+
+	return nil
+}
+
+// WriteStatusReport renders package statuses in the requested format.
+func WriteStatusReport(statuses []PackageStatus, format string, out io.Writer) error {
+	switch format {
+	case ReportFormatText:
+		for _, status := range statuses {
+			writeStatusText(&status, out)
+		}
+
+		return nil
+	case ReportFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		err := encoder.Encode(statuses)
+		if err != nil {
+			return fmt.Errorf("error encoding report as JSON: %w", err)
+		}
+
+		return nil
+	case ReportFormatMarkdown:
+		writeStatusMarkdown(statuses, out)
+
+		return nil
+	default:
+		return ValidateReportFormat(format)
+	}
+}
+
+func writeStatusText(status *PackageStatus, out io.Writer) {
+	// Not every source publishes a web page for its releases
+	urlSuffix := ""
+	if status.URL != "" {
+		urlSuffix = " (" + status.URL + ")"
+	}
+
+	switch {
+	case status.Status == StatusCurrent:
+		fmt.Fprintf(out, "%s: %s is latest\n", status.Name, status.CurrentVersion)
+	case status.Status == StatusPending && status.AvailableInHours == 0:
+		fmt.Fprintf(out, "%s: %s -> %s held back, release date unknown%s\n",
+			status.Name, status.CurrentVersion, status.LatestVersion, urlSuffix)
+	case status.Status == StatusPending:
+		fmt.Fprintf(out, "%s: %s -> %s available in %d hours%s\n",
+			status.Name, status.CurrentVersion, status.LatestVersion, status.AvailableInHours, urlSuffix)
+	default:
+		fmt.Fprintf(out, "%s: %s -> %s%s\n", status.Name, status.CurrentVersion, status.LatestVersion, urlSuffix)
+	}
+}
+
+func writeStatusMarkdown(statuses []PackageStatus, out io.Writer) {
+	fmt.Fprintln(out, "| Package | Current | Latest | Status |")
+	fmt.Fprintln(out, "| --- | --- | --- | --- |")
+
+	for _, status := range statuses {
+		latest := status.LatestVersion
+		if status.URL != "" {
+			latest = fmt.Sprintf("[%s](%s)", status.LatestVersion, status.URL)
+		}
+
+		description := status.Status
+
+		switch {
+		case status.Status == StatusPending && status.AvailableInHours == 0:
+			description = status.Status + " (release date unknown)"
+		case status.Status == StatusPending:
+			description = fmt.Sprintf("%s (available in %d hours)", status.Status, status.AvailableInHours)
+		}
+
+		fmt.Fprintf(out, "| %s | %s | %s | %s |\n", status.Name, status.CurrentVersion, latest, description)
+	}
+}
@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteStatusReport(t *testing.T) {
+	statuses := []PackageStatus{
+		{
+			Name:           "bar",
+			CurrentVersion: "1.0.0",
+			LatestVersion:  "2.0.0",
+			URL:            "https://fakegithub.com/bar",
+			Status:         StatusOutdated,
+		},
+		{
+			Name:           "baz",
+			CurrentVersion: "1.2.3",
+			LatestVersion:  "1.2.3",
+			URL:            "https://fakegithub.com/baz",
+			Status:         StatusCurrent,
+		},
+		{
+			Name:             "qux",
+			CurrentVersion:   "0.1.0",
+			LatestVersion:    "0.2.0",
+			URL:              "https://fakegithub.com/qux",
+			Status:           StatusPending,
+			AvailableInHours: 12,
+		},
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		out := bytes.Buffer{}
+		err := WriteStatusReport(statuses, ReportFormatText, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bar: 1.0.0 -> 2.0.0 (https://fakegithub.com/bar)\n"+
+			"baz: 1.2.3 is latest\n"+
+			"qux: 0.1.0 -> 0.2.0 available in 12 hours (https://fakegithub.com/qux)\n", out.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		out := bytes.Buffer{}
+		err := WriteStatusReport(statuses[2:], ReportFormatJSON, &out)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{
+			"name": "qux",
+			"currentVersion": "0.1.0",
+			"latestVersion": "0.2.0",
+			"url": "https://fakegithub.com/qux",
+			"status": "pending",
+			"availableInHours": 12
+		}]`, out.String())
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		out := bytes.Buffer{}
+		err := WriteStatusReport(statuses, ReportFormatMarkdown, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "| Package | Current | Latest | Status |\n"+
+			"| --- | --- | --- | --- |\n"+
+			"| bar | 1.0.0 | [2.0.0](https://fakegithub.com/bar) | outdated |\n"+
+			"| baz | 1.2.3 | [1.2.3](https://fakegithub.com/baz) | current |\n"+
+			"| qux | 0.1.0 | [0.2.0](https://fakegithub.com/qux) | pending (available in 12 hours) |\n", out.String())
+	})
+
+	t.Run("UnsupportedFormat", func(t *testing.T) {
+		out := bytes.Buffer{}
+		err := WriteStatusReport(statuses, "xml", &out)
+
+		assert.EqualError(t, err, `unsupported report format "xml"`)
+	})
+}
+
+func TestValidateReportFormat(t *testing.T) {
+	for _, format := range []string{ReportFormatText, ReportFormatJSON, ReportFormatMarkdown} {
+		assert.NoError(t, ValidateReportFormat(format))
+	}
+
+	assert.EqualError(t, ValidateReportFormat("xml"), `unsupported report format "xml"`)
+}
@@ -0,0 +1,359 @@
+// Package progress reports the progress of downloads, redrawing a status line on terminals
+// and printing periodic updates otherwise.
+package progress
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// terminalInterval is how often the status line is redrawn on a terminal.
+	terminalInterval = 200 * time.Millisecond
+
+	// plainInterval is how often updates are printed when output is not a terminal, such as in CI logs.
+	plainInterval = 10 * time.Second
+)
+
+// Reporter displays the progress of active tasks. It is also the writer for other output,
+// so that the status line is never interleaved with it. Reporters are safe for concurrent use.
+type Reporter struct {
+	out      io.Writer
+	terminal bool
+	now      func() time.Time
+
+	mu    sync.Mutex
+	tasks []*Task
+
+	// pending is the output written since the last newline, which the status line follows
+	pending []byte
+
+	// drawn is set when the status line is displayed on the terminal
+	drawn bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReporter creates a reporter writing to out, redrawing a status line when terminal is set.
+// Close must be called to stop reporting.
+func NewReporter(out io.Writer, terminal bool) *Reporter {
+	reporter := &Reporter{
+		out:      out,
+		terminal: terminal,
+		now:      time.Now,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	interval := plainInterval
+	if terminal {
+		interval = terminalInterval
+	}
+
+	go reporter.run(interval)
+
+	return reporter
+}
+
+// IsTerminal reports whether file is a terminal, rather than a pipe or regular file.
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (r *Reporter) run(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.render()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Close stops reporting and clears the status line.
+func (r *Reporter) Close() {
+	close(r.stop)
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+}
+
+// Write writes output, keeping the status line after it.
+func (r *Reporter) Write(data []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+
+	written, err := r.out.Write(data)
+
+	if idx := bytes.LastIndexByte(data, '\n'); idx >= 0 {
+		r.pending = append(r.pending[:0], data[idx+1:]...)
+	} else {
+		r.pending = append(r.pending, data...)
+	}
+
+	return written, err //nolint:wrapcheck
+}
+
+// LogWriter returns a writer for log records, such as for a slog handler. Records are printed on lines of their
+// own, with the pending output repeated after them, so that they neither break the status line nor split output.
+func (r *Reporter) LogWriter() io.Writer {
+	return logWriter{reporter: r}
+}
+
+type logWriter struct {
+	reporter *Reporter
+}
+
+func (w logWriter) Write(data []byte) (int, error) {
+	r := w.reporter
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+
+	if len(r.pending) > 0 {
+		fmt.Fprintln(r.out)
+	}
+
+	written, err := r.out.Write(data)
+
+	r.out.Write(r.pending) //nolint:errcheck
+
+	return written, err //nolint:wrapcheck
+}
+
+// Start begins tracking a task, such as a download, identified by name. A nil reporter returns a nil task,
+// which ignores progress.
+func (r *Reporter) Start(name string) *Task {
+	if r == nil {
+		return nil
+	}
+
+	task := &Task{
+		reporter: r,
+		name:     name,
+		started:  r.now(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tasks = append(r.tasks, task)
+
+	return task
+}
+
+func (r *Reporter) finish(task *Task) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for idx, candidate := range r.tasks {
+		if candidate == task {
+			r.tasks = append(r.tasks[:idx], r.tasks[idx+1:]...)
+
+			break
+		}
+	}
+
+	if len(r.tasks) == 0 {
+		r.clear()
+	}
+}
+
+// render displays the status of active tasks.
+func (r *Reporter) render() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.tasks) == 0 {
+		return
+	}
+
+	now := r.now()
+
+	statuses := make([]string, len(r.tasks))
+	for idx, task := range r.tasks {
+		statuses[idx] = task.status(now)
+	}
+
+	if r.terminal {
+		// Redraw the pending output, followed by the status
+		fmt.Fprintf(r.out, "\r\x1b[K%s %s", r.pending, strings.Join(statuses, ", "))
+
+		r.drawn = true
+
+		return
+	}
+
+	// Print updates on lines of their own, then repeat the pending output for what follows it
+	if len(r.pending) > 0 {
+		fmt.Fprintln(r.out)
+	}
+
+	for _, status := range statuses {
+		fmt.Fprintf(r.out, "  %s\n", status)
+	}
+
+	r.out.Write(r.pending) //nolint:errcheck
+}
+
+// clear removes the status line, restoring the pending output.
+func (r *Reporter) clear() {
+	if !r.drawn {
+		return
+	}
+
+	fmt.Fprintf(r.out, "\r\x1b[K%s", r.pending)
+
+	r.drawn = false
+}
+
+// Task tracks the progress of a single download.
+type Task struct {
+	reporter *Reporter
+	name     string
+	started  time.Time
+
+	current atomic.Int64
+	total   atomic.Int64
+}
+
+// SetTotal sets the expected size in bytes.
+func (t *Task) SetTotal(total int64) {
+	if t != nil && total > 0 {
+		t.total.Store(total)
+	}
+}
+
+// Add records bytes received.
+func (t *Task) Add(count int64) {
+	if t != nil {
+		t.current.Add(count)
+	}
+}
+
+// Reset discards the bytes received, such as when a download is retried.
+func (t *Task) Reset() {
+	if t != nil {
+		t.current.Store(0)
+	}
+}
+
+// Done stops tracking the task.
+func (t *Task) Done() {
+	if t != nil {
+		t.reporter.finish(t)
+	}
+}
+
+// status describes the task's progress, such as "fzf: 1.5 MiB / 3.0 MiB (50%), 512.0 KiB/s, 3s left".
+func (t *Task) status(now time.Time) string {
+	current := t.current.Load()
+	total := t.total.Load()
+
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%s: %s", t.name, formatBytes(current))
+
+	if total > 0 {
+		fmt.Fprintf(&builder, " / %s (%d%%)", formatBytes(total), min(current*100/total, 100)) //nolint:mnd
+	}
+
+	elapsed := now.Sub(t.started).Seconds()
+	if elapsed <= 0 || current == 0 {
+		return builder.String()
+	}
+
+	rate := float64(current) / elapsed
+
+	fmt.Fprintf(&builder, ", %s/s", formatBytes(int64(rate)))
+
+	if total > current {
+		remaining := time.Duration(float64(total-current) / rate * float64(time.Second))
+		fmt.Fprintf(&builder, ", %s left", remaining.Round(time.Second))
+	}
+
+	return builder.String()
+}
+
+// formatBytes formats a size using binary units, such as 1.5 MiB.
+func formatBytes(count int64) string {
+	const unit = 1024
+
+	if count < unit {
+		return fmt.Sprintf("%d B", count)
+	}
+
+	value := float64(count)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+
+	idx := -1
+	for value >= unit && idx < len(suffixes)-1 {
+		value /= unit
+		idx++
+	}
+
+	return fmt.Sprintf("%.1f %s", value, suffixes[idx])
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying task, for downloads made with it to report to.
+func NewContext(ctx context.Context, task *Task) context.Context {
+	return context.WithValue(ctx, contextKey{}, task)
+}
+
+// FromContext returns the task carried by ctx, or nil.
+func FromContext(ctx context.Context) *Task {
+	task, _ := ctx.Value(contextKey{}).(*Task)
+
+	return task
+}
+
+// NewReader reports bytes read from body to the task carried by ctx, if any.
+// A positive size, such as a response's Content-Length, sets the task's total.
+func NewReader(ctx context.Context, body io.Reader, size int64) io.Reader {
+	task := FromContext(ctx)
+	if task == nil {
+		return body
+	}
+
+	task.Reset()
+	task.SetTotal(size)
+
+	return &reader{body: body, task: task}
+}
+
+type reader struct {
+	body io.Reader
+	task *Task
+}
+
+func (r *reader) Read(data []byte) (int, error) {
+	count, err := r.body.Read(data)
+	r.task.Add(int64(count))
+
+	return count, err //nolint:wrapcheck
+}
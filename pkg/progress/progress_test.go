@@ -0,0 +1,243 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestReporter creates a reporter without the background ticker, rendering at a fixed time.
+func newTestReporter(out io.Writer, terminal bool, now time.Time) *Reporter {
+	return &Reporter{
+		out:      out,
+		terminal: terminal,
+		now:      func() time.Time { return now },
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[int64]string{
+		0:                  "0 B",
+		1023:               "1023 B",
+		1024:               "1.0 KiB",
+		1536:               "1.5 KiB",
+		5 * 1024 * 1024:    "5.0 MiB",
+		3 << 30:            "3.0 GiB",
+		1 << 50:            "1024.0 TiB",
+		12*1024*1024 + 512: "12.0 MiB",
+	}
+
+	for count, expected := range cases {
+		actual := formatBytes(count)
+		if actual != expected {
+			t.Errorf("Expected %d bytes to format as '%s', got '%s'", count, expected, actual)
+		}
+	}
+}
+
+func TestTaskStatus(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	reporter := newTestReporter(io.Discard, false, started)
+
+	task := reporter.Start("fzf")
+
+	status := task.status(started)
+	if status != "fzf: 0 B" {
+		t.Errorf("Expected a status without speed before data arrives, got '%s'", status)
+	}
+
+	task.Add(1024 * 1024)
+
+	status = task.status(started.Add(2 * time.Second))
+	if status != "fzf: 1.0 MiB, 512.0 KiB/s" {
+		t.Errorf("Expected a status without a total, got '%s'", status)
+	}
+
+	task.SetTotal(3 * 1024 * 1024)
+
+	status = task.status(started.Add(2 * time.Second))
+	if status != "fzf: 1.0 MiB / 3.0 MiB (33%), 512.0 KiB/s, 4s left" {
+		t.Errorf("Expected a status with progress and time remaining, got '%s'", status)
+	}
+}
+
+func TestReporter_Terminal(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	out := bytes.Buffer{}
+	reporter := newTestReporter(&out, true, started)
+
+	fmt.Fprint(reporter, "fzf: installing 0.44.1...")
+
+	task := reporter.Start("fzf")
+	task.SetTotal(2048)
+	task.Add(1024)
+
+	reporter.now = func() time.Time { return started.Add(time.Second) }
+	reporter.render()
+
+	expected := "fzf: installing 0.44.1...\r\x1b[Kfzf: installing 0.44.1... fzf: 1.0 KiB / 2.0 KiB (50%), 1.0 KiB/s, 1s left"
+	if out.String() != expected {
+		t.Errorf("Expected the status line to follow the pending output, got %q", out.String())
+	}
+
+	out.Reset()
+
+	task.Done()
+	fmt.Fprintln(reporter, " Done!")
+
+	if out.String() != "\r\x1b[Kfzf: installing 0.44.1... Done!\n" {
+		t.Errorf("Expected the status line to be cleared before further output, got %q", out.String())
+	}
+}
+
+func TestReporter_Plain(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	out := bytes.Buffer{}
+	reporter := newTestReporter(&out, false, started)
+
+	fmt.Fprint(reporter, "fzf: installing 0.44.1...")
+
+	task := reporter.Start("fzf")
+	task.Add(1024)
+
+	reporter.render()
+
+	task.Done()
+	fmt.Fprintln(reporter, " Done!")
+
+	expected := "fzf: installing 0.44.1...\n  fzf: 1.0 KiB\nfzf: installing 0.44.1... Done!\n"
+	if out.String() != expected {
+		t.Errorf("Expected updates on lines of their own, got %q", out.String())
+	}
+
+	if strings.Contains(out.String(), "\x1b") {
+		t.Error("Expected no terminal escape sequences in plain output")
+	}
+}
+
+func TestReporter_LogWriter(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	out := bytes.Buffer{}
+	reporter := newTestReporter(&out, true, started)
+
+	fmt.Fprint(reporter, "fzf: installing 0.44.1...")
+
+	task := reporter.Start("fzf")
+	task.Add(1024)
+
+	reporter.render()
+	out.Reset()
+
+	fmt.Fprint(reporter.LogWriter(), "level=WARN msg=Retrying\n")
+
+	expected := "\r\x1b[Kfzf: installing 0.44.1...\nlevel=WARN msg=Retrying\nfzf: installing 0.44.1..."
+	if out.String() != expected {
+		t.Errorf("Expected the log record on a line of its own, got %q", out.String())
+	}
+
+	out.Reset()
+
+	task.Done()
+	fmt.Fprintln(reporter, " Done!")
+
+	if out.String() != " Done!\n" {
+		t.Errorf("Expected further output to follow the pending output, got %q", out.String())
+	}
+}
+
+func TestReporter_ConcurrentTasks(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	out := bytes.Buffer{}
+	reporter := newTestReporter(&out, false, started)
+
+	var wg sync.WaitGroup
+
+	for _, name := range []string{"fzf", "jq"} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			task := reporter.Start(name)
+
+			body := NewReader(NewContext(t.Context(), task), strings.NewReader(strings.Repeat("x", 4096)), 4096)
+
+			_, err := io.Copy(io.Discard, body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			reporter.render()
+		}()
+	}
+
+	wg.Wait()
+
+	if len(reporter.tasks) != 2 {
+		t.Fatalf("Expected both tasks to be active, got %d", len(reporter.tasks))
+	}
+
+	reporter.render()
+
+	for _, expected := range []string{"  fzf: 4.0 KiB / 4.0 KiB (100%)\n", "  jq: 4.0 KiB / 4.0 KiB (100%)\n"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, out.String())
+		}
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	body := strings.NewReader("content")
+
+	reader := NewReader(context.Background(), body, 7)
+	if reader != body {
+		t.Error("Expected the body to be returned unchanged without a task")
+	}
+
+	reporter := newTestReporter(io.Discard, false, time.Now())
+	task := reporter.Start("fzf")
+	task.Add(100)
+
+	reader = NewReader(NewContext(t.Context(), task), strings.NewReader("content"), 7)
+
+	_, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if task.current.Load() != 7 || task.total.Load() != 7 {
+		t.Errorf("Expected 7 of 7 bytes, got %d of %d", task.current.Load(), task.total.Load())
+	}
+}
+
+func TestNilTask(t *testing.T) {
+	var reporter *Reporter
+
+	task := reporter.Start("fzf")
+	task.SetTotal(1)
+	task.Add(1)
+	task.Reset()
+	task.Done()
+
+	body := strings.NewReader("content")
+	if NewReader(NewContext(t.Context(), task), body, 7) != body {
+		t.Error("Expected the body to be returned unchanged for a nil task")
+	}
+}
+
+func TestReporter_Close(t *testing.T) {
+	out := bytes.Buffer{}
+	reporter := NewReporter(&out, false)
+
+	fmt.Fprintln(reporter, "output")
+	reporter.Close()
+
+	if out.String() != "output\n" {
+		t.Errorf("Expected only the written output, got %q", out.String())
+	}
+}
@@ -0,0 +1,211 @@
+package pkg
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/noizwaves/grab/pkg/github"
+	"github.com/noizwaves/grab/pkg/gitlab"
+	"github.com/noizwaves/grab/pkg/oci"
+)
+
+const (
+	SourceKindGitHub = "github"
+	SourceKindGitLab = "gitlab"
+	SourceKindGitea  = "gitea"
+	SourceKindHTTP   = "http"
+	SourceKindOCI    = "oci"
+	SourceKindFile   = "file"
+)
+
+// ErrListingUnsupported is returned by sources unable to enumerate the releases of a binary.
+var ErrListingUnsupported = errors.New("listing releases is not supported")
+
+// ErrReleaseNotFound is returned by ReleaseSource when a binary has no release of its pinned version.
+var ErrReleaseNotFound = errors.New("release not found")
+
+// Source is a backend from which the releases of a binary are discovered and downloaded.
+type Source interface {
+	// GetLatestRelease returns the newest stable release of the binary.
+	GetLatestRelease(ctx context.Context, binary *Binary) (*Release, error)
+
+	// ListReleases returns recent releases of the binary, newest first, or ErrListingUnsupported.
+	ListReleases(ctx context.Context, binary *Binary) ([]Release, error)
+
+	// ResolveAsset determines the asset to download for the binary's pinned version on a platform.
+	ResolveAsset(ctx context.Context, binary *Binary, platform, arch string) (*Asset, error)
+
+	// DownloadAsset returns the contents of a resolved asset.
+	DownloadAsset(ctx context.Context, binary *Binary, asset *Asset) ([]byte, error)
+}
+
+// BatchSource is implemented by sources able to look up the latest releases of many binaries at once.
+type BatchSource interface {
+	// GetLatestReleases returns the newest stable releases of binaries.
+	// Binaries missing from the result are to be looked up individually.
+	GetLatestReleases(ctx context.Context, binaries []*Binary) (map[*Binary]*Release, error)
+}
+
+// ReleaseSource is implemented by sources able to look up the release of a version directly,
+// which is preferable to searching listed releases, as listings are limited to recent releases.
+type ReleaseSource interface {
+	// GetRelease returns the release of the binary's pinned version, or ErrReleaseNotFound.
+	GetRelease(ctx context.Context, binary *Binary) (*Release, error)
+}
+
+// Release is a source independent description of a published release.
+type Release struct {
+	Name        string
+	TagName     string
+	URL         string
+	Body        string
+	PublishedAt time.Time
+	Prerelease  bool
+}
+
+// Asset is a downloadable file belonging to a release.
+type Asset struct {
+	// Name is the file name of the asset, which determines how it is extracted
+	Name string
+
+	// Release is the name of the release the asset belongs to
+	Release string
+
+	// URL is where the asset is downloaded from, for sources that address assets directly
+	URL string
+
+	// ChecksumURL is where the asset's SHA-256 checksum is published, when available
+	ChecksumURL string
+
+	// Digest identifies the asset's content, for content addressed sources
+	Digest string
+
+	// ID identifies the asset to its source's API, when known from resolving it
+	ID int64
+
+	// Size is the asset's size in bytes, when known before downloading
+	Size int64
+}
+
+// Sources constructs the Source for a binary based on its source kind.
+type Sources struct {
+	// GitHubClient is the client for github.com
+	GitHubClient github.Client
+
+	// NewGitHubEnterpriseClient constructs a client for the GitHub Enterprise Server instance at a host
+	NewGitHubEnterpriseClient func(host string) github.Client
+
+	// NewGitLabClient constructs a client for the GitLab instance at a host
+	NewGitLabClient func(host string) gitlab.Client
+
+	// NewGiteaClient constructs a client for the Gitea or Forgejo instance at a host
+	NewGiteaClient func(host string) github.Client
+
+	// NewOCIClient constructs a client for the OCI registry at a host
+	NewOCIClient func(host string) oci.Client
+
+	// HTTPClient performs requests for sources without a dedicated client
+	HTTPClient *http.Client
+}
+
+func (s *Sources) SourceFor(binary *Binary) (Source, error) {
+	switch binary.SourceKind {
+	case SourceKindGitHub:
+		if binary.Host == "" || binary.Host == github.DefaultHost {
+			return &GitHubSource{Client: s.GitHubClient}, nil
+		}
+
+		return &GitHubSource{Client: s.NewGitHubEnterpriseClient(binary.Host)}, nil
+	case SourceKindGitLab:
+		return &GitLabSource{Client: s.NewGitLabClient(binary.Host)}, nil
+	case SourceKindGitea:
+		// Gitea's releases API is compatible with GitHub's
+		return &GitHubSource{Client: s.NewGiteaClient(binary.Host)}, nil
+	case SourceKindHTTP:
+		return &HTTPSource{Client: s.HTTPClient}, nil
+	case SourceKindOCI:
+		return &OCISource{Client: s.NewOCIClient(binary.Host)}, nil
+	case SourceKindFile:
+		return &FileSource{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported source kind %q for package %q", binary.SourceKind, binary.Name)
+	}
+}
+
+// releasesFromTags returns releases for the tags produced by the binary's release name template, highest version first.
+// This suits sources that publish versions as tags without release metadata.
+func releasesFromTags(binary *Binary, tags []string) []Release {
+	type versionedTag struct {
+		tag     string
+		version string
+	}
+
+	matched := make([]versionedTag, 0, len(tags))
+
+	for _, tag := range tags {
+		version, ok := versionOfTag(binary, tag)
+		if ok {
+			matched = append(matched, versionedTag{tag: tag, version: version})
+		}
+	}
+
+	slices.SortFunc(matched, func(a, b versionedTag) int {
+		return compareVersions(b.version, a.version)
+	})
+
+	output := make([]Release, len(matched))
+	for idx, match := range matched {
+		output[idx] = Release{
+			Name:    match.tag,
+			TagName: match.tag,
+		}
+	}
+
+	return output
+}
+
+// versionOfTag extracts the version from a tag, accepting only tags produced by the binary's tag template.
+// Tags such as "latest" or "v1.2.3-rc.1" are ignored.
+func versionOfTag(binary *Binary, tag string) (string, bool) {
+	version, err := extractReleaseVersion(binary, &Release{Name: tag})
+	if err != nil {
+		return "", false
+	}
+
+	versioned := *binary
+	versioned.PinnedVersion = version
+
+	rendered, err := versioned.GetReleaseName()
+
+	return version, err == nil && rendered == tag
+}
+
+// compareVersions orders versions by their dot separated numeric components,
+// comparing non-numeric components as strings.
+func compareVersions(a, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+
+	for idx := 0; idx < len(partsA) && idx < len(partsB); idx++ {
+		numA, errA := strconv.Atoi(partsA[idx])
+		numB, errB := strconv.Atoi(partsB[idx])
+
+		result := cmp.Compare(numA, numB)
+		if errA != nil || errB != nil {
+			result = strings.Compare(partsA[idx], partsB[idx])
+		}
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return cmp.Compare(len(partsA), len(partsB))
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// FileSource provides binaries from a local directory laid out as <org>/<repo>/<tag>/<asset>,
+// such as a mirror copied onto hosts without internet access.
+type FileSource struct{}
+
+func (s *FileSource) GetLatestRelease(ctx context.Context, binary *Binary) (*Release, error) {
+	releases, err := s.ListReleases(ctx, binary)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no directories in %s match the release name template", repositoryDir(binary))
+	}
+
+	return &releases[0], nil
+}
+
+// ListReleases returns the tag directories of the repository, highest version first.
+func (s *FileSource) ListReleases(_ context.Context, binary *Binary) ([]Release, error) {
+	dir := repositoryDir(binary)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading releases directory: %w", err)
+	}
+
+	tags := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			tags = append(tags, entry.Name())
+		}
+	}
+
+	// Directories carry no publication time, as copying a mirror resets their modification times
+	return releasesFromTags(binary, tags), nil
+}
+
+func (s *FileSource) ResolveAsset(_ context.Context, binary *Binary, platform, arch string) (*Asset, error) {
+	assetName, err := binary.GetAssetFileName(platform, arch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting asset name: %w", err)
+	}
+
+	releaseName, err := binary.GetReleaseName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting release name: %w", err)
+	}
+
+	return &Asset{
+		Name:    assetName,
+		Release: releaseName,
+	}, nil
+}
+
+func (s *FileSource) DownloadAsset(ctx context.Context, binary *Binary, asset *Asset) ([]byte, error) {
+	assetPath := filepath.Join(repositoryDir(binary), asset.Release, asset.Name)
+
+	slog.DebugContext(ctx, "Reading asset from disk", "path", assetPath)
+
+	data, err := os.ReadFile(assetPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("asset %q not found at %s", asset.Name, assetPath)
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading asset: %w", err)
+	}
+
+	return data, nil
+}
+
+func repositoryDir(binary *Binary) string {
+	return filepath.Join(binary.Path, binary.Org, binary.Repo)
+}
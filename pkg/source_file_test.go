@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/noizwaves/grab/pkg/internal/asserth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeFileMirror creates a directory laid out as <org>/<repo>/<tag>/<asset> containing a script per tag.
+func makeFileMirror(t *testing.T, tags ...string) string {
+	t.Helper()
+
+	root := t.TempDir()
+
+	for _, tag := range tags {
+		dir := filepath.Join(root, "foo", "bar", tag)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+
+		script := "#!/usr/bin/env bash\necho '" + tag + "'"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "bar-linux"), []byte(script), 0o644))
+	}
+
+	return root
+}
+
+func TestFileSource(t *testing.T) {
+	root := makeFileMirror(t, "v1.9.0", "v1.10.0", "latest")
+
+	base := Binary{
+		Name:         "bar",
+		SourceKind:   SourceKindFile,
+		Path:         root,
+		Org:          "foo",
+		Repo:         "bar",
+		releaseName:  "v{{ .Version }}",
+		ReleaseRegex: regexp.MustCompile(`\d+\.\d+\.\d+`),
+		fileName: map[string]string{
+			"linux,amd64": "bar-linux",
+		},
+	}
+
+	source := &FileSource{}
+
+	t.Run("LatestRelease", func(t *testing.T) {
+		release, err := source.GetLatestRelease(t.Context(), &base)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.10.0", release.TagName)
+		assert.True(t, release.PublishedAt.IsZero())
+	})
+
+	t.Run("DownloadAsset", func(t *testing.T) {
+		binary := base
+		binary.PinnedVersion = "1.9.0"
+
+		asset, err := source.ResolveAsset(t.Context(), &binary, "linux", "amd64")
+		require.NoError(t, err)
+		assert.Equal(t, &Asset{Name: "bar-linux", Release: "v1.9.0"}, asset)
+
+		data, err := source.DownloadAsset(t.Context(), &binary, asset)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "echo 'v1.9.0'")
+	})
+
+	t.Run("MissingAsset", func(t *testing.T) {
+		binary := base
+		binary.PinnedVersion = "2.0.0"
+
+		asset, err := source.ResolveAsset(t.Context(), &binary, "linux", "amd64")
+		require.NoError(t, err)
+
+		_, err = source.DownloadAsset(t.Context(), &binary, asset)
+		assert.ErrorContains(t, err, `asset "bar-linux" not found at `)
+	})
+}
+
+// Installs a package from a local directory, as on hosts without internet access.
+func TestInstall_FileSource(t *testing.T) {
+	root := makeFileMirror(t, "v1.0.0")
+
+	configDir := t.TempDir()
+	binDir := t.TempDir()
+
+	packageSpec := "apiVersion: grab.noizwaves.com/v1alpha1\n" +
+		"kind: Package\n" +
+		"metadata:\n" +
+		"  name: bar\n" +
+		"spec:\n" +
+		"  file:\n" +
+		"    path: " + root + "\n" +
+		"    org: foo\n" +
+		"    repo: bar\n" +
+		"    name: v{{ .Version }}\n" +
+		"    versionRegex: \\d+\\.\\d+\\.\\d+\n" +
+		"    fileName:\n" +
+		"      darwin,amd64: bar-linux\n" +
+		"      darwin,arm64: bar-linux\n" +
+		"      linux,amd64: bar-linux\n" +
+		"      linux,arm64: bar-linux\n" +
+		"  program:\n" +
+		"    versionArgs: [--version]\n" +
+		"    versionRegex: \\d+\\.\\d+\\.\\d+\n"
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "repository"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "repository", "bar.yml"), []byte(packageSpec), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte("packages:\n  bar: 1.0.0\n"), 0o644))
+
+	gCtx, err := NewGrabContext(configDir, binDir)
+	require.NoError(t, err)
+
+	installer := Installer{
+		Sources: &Sources{},
+	}
+
+	out := bytes.Buffer{}
+	err = installer.Install(t.Context(), gCtx, "", &out)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "bar: installing 1.0.0... Done!")
+
+	barPath := filepath.Join(binDir, "bar")
+	asserth.CommandSucceeds(t, barPath)
+	asserth.CommandStdoutContains(t, barPath, "1.0.0")
+}
@@ -0,0 +1,236 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/noizwaves/grab/pkg/github"
+)
+
+// GitHubSource provides binaries published as GitHub Release assets,
+// or through a GitHub compatible releases API such as Gitea's.
+type GitHubSource struct {
+	Client github.Client
+}
+
+func (s *GitHubSource) GetLatestRelease(ctx context.Context, binary *Binary) (*Release, error) {
+	release, err := s.Client.GetLatestRelease(ctx, binary.Org, binary.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching latest release: %w", err)
+	}
+
+	output := newReleaseFromGitHub(release)
+
+	return &output, nil
+}
+
+// GetLatestReleases looks up latest releases in batches when the client supports it,
+// which requires an access token. Otherwise no releases are returned.
+func (s *GitHubSource) GetLatestReleases(ctx context.Context, binaries []*Binary) (map[*Binary]*Release, error) {
+	output := make(map[*Binary]*Release, len(binaries))
+
+	client, ok := s.Client.(github.BatchClient)
+	if !ok {
+		return output, nil
+	}
+
+	repos := make([]github.Repository, len(binaries))
+	for idx, binary := range binaries {
+		repos[idx] = github.Repository{Owner: binary.Org, Name: binary.Repo}
+	}
+
+	releases, err := client.GetLatestReleases(ctx, repos)
+	if errors.Is(err, github.ErrBatchUnavailable) {
+		return output, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching latest releases: %w", err)
+	}
+
+	for idx, binary := range binaries {
+		if release, ok := releases[repos[idx]]; ok {
+			converted := newReleaseFromGitHub(release)
+			output[binary] = &converted
+		}
+	}
+
+	return output, nil
+}
+
+func (s *GitHubSource) GetRelease(ctx context.Context, binary *Binary) (*Release, error) {
+	releaseName, err := binary.GetReleaseName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting release name: %w", err)
+	}
+
+	release, err := s.Client.GetReleaseByTag(ctx, binary.Org, binary.Repo, releaseName)
+	if errors.Is(err, github.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %q in %s/%s", ErrReleaseNotFound, releaseName, binary.Org, binary.Repo)
+	} else if err != nil {
+		return nil, fmt.Errorf("error fetching release %q: %w", releaseName, err)
+	}
+
+	output := newReleaseFromGitHub(release)
+
+	return &output, nil
+}
+
+func (s *GitHubSource) ListReleases(ctx context.Context, binary *Binary) ([]Release, error) {
+	releases, err := s.Client.ListReleases(ctx, binary.Org, binary.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("error listing releases: %w", err)
+	}
+
+	output := make([]Release, len(releases))
+	for idx := range releases {
+		output[idx] = newReleaseFromGitHub(&releases[idx])
+	}
+
+	return output, nil
+}
+
+// ResolveAsset renders the asset's name, and confirms that the release has an asset of that name
+// before anything is downloaded.
+func (s *GitHubSource) ResolveAsset(ctx context.Context, binary *Binary, platform, arch string) (*Asset, error) {
+	assetName, err := binary.GetAssetFileName(platform, arch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting asset filename: %w", err)
+	}
+
+	releaseName, err := binary.GetReleaseName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting release name: %w", err)
+	}
+
+	asset := &Asset{
+		Name:    assetName,
+		Release: releaseName,
+	}
+
+	release, err := s.Client.GetReleaseByTag(ctx, binary.Org, binary.Repo, releaseName)
+
+	switch {
+	case errors.Is(err, github.ErrNotFound):
+		return nil, fmt.Errorf("release %q not found in %s/%s; check the release name of %s (%w)",
+			releaseName, binary.Org, binary.Repo, binary.Name, err)
+	case err != nil && ctx.Err() != nil:
+		return nil, fmt.Errorf("error fetching release %q: %w", releaseName, ctx.Err())
+	case err != nil:
+		// The download may still succeed, such as when only the API is rate limited
+		slog.DebugContext(ctx, "Unable to confirm asset exists", "release", releaseName, "asset", assetName, "error", err)
+
+		return asset, nil
+	}
+
+	idx := slices.IndexFunc(release.Assets, func(a github.Asset) bool { return a.Name == assetName })
+	if idx == -1 {
+		return nil, missingAssetError(binary, release, assetName, platform, arch)
+	}
+
+	asset.Size = release.Assets[idx].Size
+	asset.ID = release.Assets[idx].ID
+
+	return asset, nil
+}
+
+// missingAssetError explains that a release has no asset named assetName, suggesting those likely intended.
+func missingAssetError(binary *Binary, release *github.Release, assetName, platform, arch string) error {
+	names := make([]string, len(release.Assets))
+	for idx, releaseAsset := range release.Assets {
+		names[idx] = releaseAsset.Name
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("asset %q not found in release %q, which has no assets", assetName, release.TagName)
+	}
+
+	suggestions := suggestAssetNames(assetName, names, platform, arch)
+	if len(suggestions) == 0 {
+		// Only checksums and signatures, which are never suggested, were published
+		return fmt.Errorf("asset %q not found in release %q; available assets: %s",
+			assetName, release.TagName, strings.Join(names, ", "))
+	}
+
+	return fmt.Errorf("asset %q not found in release %q; did you mean %s? "+
+		"Fix the fileName entry for %q of %s",
+		assetName, release.TagName, quoteJoin(suggestions), platform+","+arch, binary.Name)
+}
+
+// quoteJoin quotes and joins values as a list, such as "a", "b" or "c".
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for idx, value := range values {
+		quoted[idx] = fmt.Sprintf("%q", value)
+	}
+
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+func (s *GitHubSource) DownloadAsset(ctx context.Context, binary *Binary, asset *Asset) ([]byte, error) {
+	data, err := s.downloadReleaseAsset(ctx, binary, asset)
+
+	switch {
+	case errors.Is(err, github.ErrNotFound):
+		return nil, s.describeMissingAsset(ctx, binary, asset, err)
+	case errors.Is(err, github.ErrUnauthorized):
+		return nil, fmt.Errorf("access to %s/%s was denied; check that the access token is set and can read "+
+			"the repository: %w", binary.Org, binary.Repo, err)
+	case err != nil:
+		return nil, fmt.Errorf("error downloading release asset: %w", err)
+	}
+
+	return data, nil
+}
+
+// downloadReleaseAsset downloads by the ID found while resolving the asset when the client supports it,
+// so that the release is not looked up again.
+func (s *GitHubSource) downloadReleaseAsset(ctx context.Context, binary *Binary, asset *Asset) ([]byte, error) {
+	if client, ok := s.Client.(github.AssetClient); ok && asset.ID != 0 {
+		return client.DownloadReleaseAssetByID(ctx, binary.Org, binary.Repo, asset.Release, asset.Name, asset.ID)
+	}
+
+	return s.Client.DownloadReleaseAsset(ctx, binary.Org, binary.Repo, asset.Release, asset.Name)
+}
+
+// describeMissingAsset explains a download that was not found, listing the assets the release does have.
+func (s *GitHubSource) describeMissingAsset(ctx context.Context, binary *Binary, asset *Asset, cause error) error {
+	release, err := s.Client.GetReleaseByTag(ctx, binary.Org, binary.Repo, asset.Release)
+
+	switch {
+	case errors.Is(err, github.ErrNotFound):
+		return fmt.Errorf("release %q not found in %s/%s (%w)", asset.Release, binary.Org, binary.Repo, cause)
+	case err != nil:
+		return fmt.Errorf("error downloading release asset: %w", cause)
+	case len(release.Assets) == 0:
+		return fmt.Errorf("asset %q not found in release %q (%w); the release has no assets",
+			asset.Name, asset.Release, cause)
+	}
+
+	names := make([]string, len(release.Assets))
+	for idx, releaseAsset := range release.Assets {
+		names[idx] = releaseAsset.Name
+	}
+
+	return fmt.Errorf("asset %q not found in release %q (%w); available assets: %s",
+		asset.Name, asset.Release, cause, strings.Join(names, ", "))
+}
+
+func newReleaseFromGitHub(release *github.Release) Release {
+	return Release{
+		Name:        release.Name,
+		TagName:     release.TagName,
+		URL:         release.URL,
+		Body:        release.Body,
+		PublishedAt: release.PublishedAt,
+		Prerelease:  release.Prerelease,
+	}
+}
@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/noizwaves/grab/pkg/gitlab"
+)
+
+// GitLabSource provides binaries published as GitLab Release asset links.
+type GitLabSource struct {
+	Client gitlab.Client
+}
+
+func (s *GitLabSource) GetLatestRelease(ctx context.Context, binary *Binary) (*Release, error) {
+	release, err := s.Client.GetLatestRelease(ctx, binary.Project)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching latest GitLab release: %w", err)
+	}
+
+	output := newReleaseFromGitLab(release)
+
+	return &output, nil
+}
+
+func (s *GitLabSource) GetRelease(ctx context.Context, binary *Binary) (*Release, error) {
+	releaseName, err := binary.GetReleaseName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting release name: %w", err)
+	}
+
+	release, err := s.Client.GetReleaseByTag(ctx, binary.Project, releaseName)
+	if errors.Is(err, gitlab.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %q in %s", ErrReleaseNotFound, releaseName, binary.Project)
+	} else if err != nil {
+		return nil, fmt.Errorf("error fetching GitLab release %q: %w", releaseName, err)
+	}
+
+	output := newReleaseFromGitLab(release)
+
+	return &output, nil
+}
+
+func (s *GitLabSource) ListReleases(ctx context.Context, binary *Binary) ([]Release, error) {
+	releases, err := s.Client.ListReleases(ctx, binary.Project)
+	if err != nil {
+		return nil, fmt.Errorf("error listing GitLab releases: %w", err)
+	}
+
+	output := make([]Release, len(releases))
+	for idx := range releases {
+		output[idx] = newReleaseFromGitLab(&releases[idx])
+	}
+
+	return output, nil
+}
+
+func (s *GitLabSource) ResolveAsset(_ context.Context, binary *Binary, platform, arch string) (*Asset, error) {
+	assetName, err := binary.GetAssetFileName(platform, arch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting asset filename: %w", err)
+	}
+
+	releaseName, err := binary.GetReleaseName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting release name: %w", err)
+	}
+
+	return &Asset{
+		Name:    assetName,
+		Release: releaseName,
+	}, nil
+}
+
+func (s *GitLabSource) DownloadAsset(ctx context.Context, binary *Binary, asset *Asset) ([]byte, error) {
+	data, err := s.Client.DownloadReleaseAsset(ctx, binary.Project, asset.Release, asset.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading GitLab release asset: %w", err)
+	}
+
+	return data, nil
+}
+
+func newReleaseFromGitLab(release *gitlab.Release) Release {
+	return Release{
+		Name:        release.Name,
+		TagName:     release.TagName,
+		URL:         release.Links.Self,
+		Body:        release.Description,
+		PublishedAt: release.ReleasedAt,
+		Prerelease:  release.Upcoming,
+	}
+}
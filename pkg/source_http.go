@@ -0,0 +1,205 @@
+package pkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/noizwaves/grab/pkg/progress"
+)
+
+// HTTPSource provides binaries downloaded from URL templates, such as those published on vendor CDNs.
+type HTTPSource struct {
+	// Client performs requests, defaulting to http.DefaultClient
+	Client *http.Client
+}
+
+func (s *HTTPSource) GetLatestRelease(ctx context.Context, binary *Binary) (*Release, error) {
+	if binary.LatestVersionURL == "" {
+		return nil, fmt.Errorf("no latest version URL configured for %q", binary.Name)
+	}
+
+	data, err := s.get(ctx, binary.LatestVersionURL)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching latest version: %w", err)
+	}
+
+	value := strings.TrimSpace(string(data))
+
+	if binary.LatestVersionJSONPath != "" {
+		value, err = lookupJSONPath(data, binary.LatestVersionJSONPath)
+		if err != nil {
+			return nil, fmt.Errorf("error extracting latest version: %w", err)
+		}
+	}
+
+	return &Release{
+		Name:    value,
+		TagName: value,
+	}, nil
+}
+
+// ListReleases is unsupported, as URL templates describe how to download any version but not which exist.
+func (s *HTTPSource) ListReleases(_ context.Context, _ *Binary) ([]Release, error) {
+	return nil, fmt.Errorf("http sources: %w", ErrListingUnsupported)
+}
+
+func (s *HTTPSource) ResolveAsset(_ context.Context, binary *Binary, platform, arch string) (*Asset, error) {
+	assetURL, err := binary.GetAssetFileName(platform, arch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting download URL: %w", err)
+	}
+
+	parsedURL, err := url.Parse(assetURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download URL %q: %w", assetURL, err)
+	}
+
+	checksumURL, err := binary.GetChecksumURL(platform, arch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting checksum URL: %w", err)
+	}
+
+	return &Asset{
+		Name:        path.Base(parsedURL.Path),
+		Release:     binary.PinnedVersion,
+		URL:         assetURL,
+		ChecksumURL: checksumURL,
+	}, nil
+}
+
+func (s *HTTPSource) DownloadAsset(ctx context.Context, _ *Binary, asset *Asset) ([]byte, error) {
+	slog.DebugContext(ctx, "Downloading asset over HTTP", "url", asset.URL)
+
+	data, err := s.get(ctx, asset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading asset: %w", err)
+	}
+
+	if asset.ChecksumURL == "" {
+		return data, nil
+	}
+
+	// The checksum is not part of the asset's download progress
+	checksums, err := s.get(progress.NewContext(ctx, nil), asset.ChecksumURL)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading checksum: %w", err)
+	}
+
+	err = verifyChecksum(asset.Name, data, checksums)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *HTTPSource) get(ctx context.Context, targetURL string) ([]byte, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q from %s", resp.Status, targetURL)
+	}
+
+	data, err := io.ReadAll(progress.NewReader(ctx, resp.Body, resp.ContentLength))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return data, nil
+}
+
+// verifyChecksum compares the SHA-256 digest of data against a checksum file.
+// The file either holds a single digest, or lines of "<digest>  <file name>" as produced by sha256sum.
+func verifyChecksum(assetName string, data, checksums []byte) error {
+	expected := ""
+
+	lines := strings.Split(strings.TrimSpace(string(checksums)), "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+
+		switch {
+		case len(fields) == 1 && len(lines) == 1:
+			expected = fields[0]
+		case len(fields) >= 2 && strings.TrimPrefix(fields[len(fields)-1], "*") == assetName:
+			expected = fields[0]
+		}
+	}
+
+	if expected == "" {
+		return fmt.Errorf("no checksum found for %q", assetName)
+	}
+
+	digest := sha256.Sum256(data)
+	actual := hex.EncodeToString(digest[:])
+
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("checksum mismatch for %q: expected %s, got %s", assetName, expected, actual)
+	}
+
+	return nil
+}
+
+// lookupJSONPath extracts a value from a JSON document using a dot separated path of keys and indexes,
+// such as "version" or "0.version".
+func lookupJSONPath(data []byte, jsonPath string) (string, error) {
+	var current any
+
+	err := json.Unmarshal(data, &current)
+	if err != nil {
+		return "", fmt.Errorf("error parsing response as JSON: %w", err)
+	}
+
+	for _, segment := range strings.Split(strings.TrimPrefix(jsonPath, "."), ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return "", fmt.Errorf("key %q not found in JSON path %q", segment, jsonPath)
+			}
+
+			current = value
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", fmt.Errorf("index %q out of range in JSON path %q", segment, jsonPath)
+			}
+
+			current = node[idx]
+		default:
+			return "", fmt.Errorf("cannot descend into %q in JSON path %q", segment, jsonPath)
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, nil
+	case float64, bool:
+		return fmt.Sprint(value), nil
+	default:
+		return "", fmt.Errorf("JSON path %q does not refer to a scalar value", jsonPath)
+	}
+}
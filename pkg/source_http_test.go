@@ -0,0 +1,204 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/noizwaves/grab/pkg/internal/asserth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSource(t *testing.T) {
+	assetData := []byte("#!/usr/bin/env bash\necho 'v1.30.0'")
+	digest := sha256.Sum256(assetData)
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/release/stable.txt":
+			_, _ = responseWriter.Write([]byte("v1.30.0\n"))
+		case "/release/index.json":
+			_, _ = responseWriter.Write([]byte(`[{"version": "v1.30.0"}, {"version": "v1.29.0"}]`))
+		case "/release/v1.30.0/bin/linux/amd64/kubectl":
+			_, _ = responseWriter.Write(assetData)
+		case "/release/v1.30.0/bin/linux/amd64/kubectl.sha256":
+			_, _ = responseWriter.Write([]byte(hex.EncodeToString(digest[:])))
+		case "/release/v1.30.0/SHA256SUMS":
+			_, _ = responseWriter.Write([]byte("0000  kubectl-other\ndeadbeef  kubectl\n"))
+		default:
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	base := Binary{
+		Name:             "kubectl",
+		PinnedVersion:    "1.30.0",
+		SourceKind:       SourceKindHTTP,
+		releaseName:      "{{ .Version }}",
+		LatestVersionURL: server.URL + "/release/stable.txt",
+		fileName: map[string]string{
+			"linux,amd64": server.URL + "/release/v{{ .Version }}/bin/linux/amd64/kubectl",
+		},
+		checksumURL: map[string]string{
+			"linux,amd64": server.URL + "/release/v{{ .Version }}/bin/linux/amd64/kubectl.sha256",
+		},
+	}
+
+	source := &HTTPSource{}
+
+	t.Run("LatestVersionText", func(t *testing.T) {
+		release, err := source.GetLatestRelease(t.Context(), &base)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.30.0", release.Name)
+	})
+
+	t.Run("LatestVersionJSON", func(t *testing.T) {
+		binary := base
+		binary.LatestVersionURL = server.URL + "/release/index.json"
+		binary.LatestVersionJSONPath = "0.version"
+
+		release, err := source.GetLatestRelease(t.Context(), &binary)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.30.0", release.Name)
+	})
+
+	t.Run("NoLatestVersionURL", func(t *testing.T) {
+		binary := base
+		binary.LatestVersionURL = ""
+
+		_, err := source.GetLatestRelease(t.Context(), &binary)
+
+		assert.EqualError(t, err, `no latest version URL configured for "kubectl"`)
+	})
+
+	t.Run("ListReleasesUnsupported", func(t *testing.T) {
+		_, err := source.ListReleases(t.Context(), &base)
+
+		assert.ErrorIs(t, err, ErrListingUnsupported)
+	})
+
+	t.Run("DownloadVerified", func(t *testing.T) {
+		asset, err := source.ResolveAsset(t.Context(), &base, "linux", "amd64")
+		require.NoError(t, err)
+
+		assert.Equal(t, "kubectl", asset.Name)
+		assert.Equal(t, server.URL+"/release/v1.30.0/bin/linux/amd64/kubectl", asset.URL)
+		assert.Equal(t, server.URL+"/release/v1.30.0/bin/linux/amd64/kubectl.sha256", asset.ChecksumURL)
+
+		data, err := source.DownloadAsset(t.Context(), &base, asset)
+
+		require.NoError(t, err)
+		assert.Equal(t, assetData, data)
+	})
+
+	t.Run("DownloadChecksumMismatch", func(t *testing.T) {
+		binary := base
+		binary.checksumURL = map[string]string{
+			"linux,amd64": server.URL + "/release/v{{ .Version }}/SHA256SUMS",
+		}
+
+		asset, err := source.ResolveAsset(t.Context(), &binary, "linux", "amd64")
+		require.NoError(t, err)
+
+		_, err = source.DownloadAsset(t.Context(), &binary, asset)
+
+		assert.ErrorContains(t, err, `checksum mismatch for "kubectl": expected deadbeef`)
+	})
+
+	t.Run("DownloadNotFound", func(t *testing.T) {
+		_, err := source.DownloadAsset(t.Context(), &base, &Asset{Name: "missing", URL: server.URL + "/missing"})
+
+		assert.ErrorContains(t, err, `unexpected status "404 Not Found"`)
+	})
+}
+
+// Test that any version of an http package can be installed, as its releases cannot be listed.
+func TestHTTPSourceInstallVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/v0.9.0/bar" {
+			responseWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = responseWriter.Write([]byte("#!/usr/bin/env bash\necho '0.9.0'"))
+	}))
+	defer server.Close()
+
+	configDir := t.TempDir()
+	binDir := t.TempDir()
+
+	packageSpec := "apiVersion: grab.noizwaves.com/v1alpha1\n" +
+		"kind: Package\n" +
+		"metadata:\n" +
+		"  name: bar\n" +
+		"spec:\n" +
+		"  http:\n" +
+		"    versionRegex: \\d+\\.\\d+\\.\\d+\n" +
+		"    url:\n" +
+		"      darwin,amd64: " + server.URL + "/v{{ .Version }}/bar\n" +
+		"      darwin,arm64: " + server.URL + "/v{{ .Version }}/bar\n" +
+		"      linux,amd64: " + server.URL + "/v{{ .Version }}/bar\n" +
+		"      linux,arm64: " + server.URL + "/v{{ .Version }}/bar\n" +
+		"  program:\n" +
+		"    versionArgs: [--version]\n" +
+		"    versionRegex: \\d+\\.\\d+\\.\\d+\n"
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "repository"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "repository", "bar.yml"), []byte(packageSpec), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte("packages:\n  bar: 1.0.0\n"), 0o644))
+
+	gCtx, err := NewGrabContext(configDir, binDir)
+	require.NoError(t, err)
+
+	installer := Installer{
+		Sources: &Sources{},
+	}
+
+	out := bytes.Buffer{}
+	err = installer.InstallVersion(t.Context(), gCtx, "bar", "0.9.0", &out)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "bar: installing 0.9.0... Done!")
+	asserth.CommandStdoutContains(t, filepath.Join(binDir, "bar"), "0.9.0")
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	document := []byte(`{"current": {"version": "1.2.3", "build": 42}, "tags": ["v2", "v1"]}`)
+
+	tests := []struct {
+		path        string
+		expected    string
+		expectError string
+	}{
+		{path: "current.version", expected: "1.2.3"},
+		{path: ".current.version", expected: "1.2.3"},
+		{path: "current.build", expected: "42"},
+		{path: "tags.1", expected: "v1"},
+		{path: "current.missing", expectError: `key "missing" not found`},
+		{path: "tags.5", expectError: `index "5" out of range`},
+		{path: "current", expectError: "does not refer to a scalar value"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.path, func(t *testing.T) {
+			result, err := lookupJSONPath(document, testCase.path)
+
+			if testCase.expectError != "" {
+				assert.ErrorContains(t, err, testCase.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package pkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/noizwaves/grab/pkg/oci"
+)
+
+// imageConfigMediaTypes are the config media types recording when an image was created.
+var imageConfigMediaTypes = []string{oci.MediaTypeImageConfig, oci.MediaTypeDockerImageConfig}
+
+// OCISource provides binaries published as layers of OCI artifacts, such as those pushed with ORAS.
+type OCISource struct {
+	Client oci.Client
+}
+
+func (s *OCISource) GetLatestRelease(ctx context.Context, binary *Binary) (*Release, error) {
+	releases, err := s.ListReleases(ctx, binary)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no tags of %q match the tag template", binary.Repo)
+	}
+
+	latest := releases[0]
+
+	// Tags carry no timestamps, so only the latest is dated for the minimum release age
+	latest.PublishedAt, err = s.getCreated(ctx, binary, latest.TagName)
+	if err != nil {
+		slog.DebugContext(ctx, "Unable to determine when tag was created", "tag", latest.TagName, "error", err)
+	}
+
+	return &latest, nil
+}
+
+// ListReleases returns the tags produced by the binary's tag template, highest version first.
+func (s *OCISource) ListReleases(ctx context.Context, binary *Binary) ([]Release, error) {
+	tags, err := s.Client.ListTags(ctx, binary.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("error listing OCI tags: %w", err)
+	}
+
+	return releasesFromTags(binary, tags), nil
+}
+
+func (s *OCISource) ResolveAsset(ctx context.Context, binary *Binary, platform, arch string) (*Asset, error) {
+	tag, err := binary.GetReleaseName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting tag: %w", err)
+	}
+
+	manifest, err := s.Client.GetManifest(ctx, binary.Repo, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching manifest for %q: %w", tag, err)
+	}
+
+	if manifest.IsIndex() {
+		manifest, err = s.getPlatformManifest(ctx, binary, manifest, platform, arch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	layer, err := selectLayer(binary, manifest, platform, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Asset{
+		Name:    layerFileName(binary, layer),
+		Release: tag,
+		Digest:  layer.Digest,
+		Size:    layer.Size,
+	}, nil
+}
+
+func (s *OCISource) DownloadAsset(ctx context.Context, binary *Binary, asset *Asset) ([]byte, error) {
+	slog.DebugContext(ctx, "Downloading layer from registry", "repository", binary.Repo, "digest", asset.Digest)
+
+	data, err := s.Client.GetBlob(ctx, binary.Repo, asset.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading layer: %w", err)
+	}
+
+	err = verifyDigest(asset.Digest, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *OCISource) getPlatformManifest(
+	ctx context.Context, binary *Binary, index *oci.Manifest, platform, arch string,
+) (*oci.Manifest, error) {
+	for _, descriptor := range index.Manifests {
+		if descriptor.Platform == nil ||
+			descriptor.Platform.OS != platform || descriptor.Platform.Architecture != arch {
+			continue
+		}
+
+		manifest, err := s.Client.GetManifest(ctx, binary.Repo, descriptor.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching manifest for %s/%s: %w", platform, arch, err)
+		}
+
+		return manifest, nil
+	}
+
+	return nil, fmt.Errorf("image index has no manifest for %s/%s", platform, arch)
+}
+
+// getCreated returns when the manifest of a tag was created, from its annotations or else its image config.
+// The zero time is returned when neither records it.
+func (s *OCISource) getCreated(ctx context.Context, binary *Binary, tag string) (time.Time, error) {
+	manifest, err := s.Client.GetManifest(ctx, binary.Repo, tag)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error fetching manifest for %q: %w", tag, err)
+	}
+
+	if manifest.IsIndex() && manifest.Annotations[oci.AnnotationCreated] == "" && len(manifest.Manifests) > 0 {
+		// Platforms are built together, so any one dates the index
+		manifest, err = s.Client.GetManifest(ctx, binary.Repo, manifest.Manifests[0].Digest)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error fetching platform manifest for %q: %w", tag, err)
+		}
+	}
+
+	if created := manifest.Annotations[oci.AnnotationCreated]; created != "" {
+		return time.Parse(time.RFC3339, created)
+	}
+
+	// Artifacts pushed by ORAS have empty configs
+	if manifest.Config == nil || !slices.Contains(imageConfigMediaTypes, manifest.Config.MediaType) {
+		return time.Time{}, nil
+	}
+
+	data, err := s.Client.GetBlob(ctx, binary.Repo, manifest.Config.Digest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error fetching image config: %w", err)
+	}
+
+	var config oci.ImageConfig
+
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing image config: %w", err)
+	}
+
+	return config.Created, nil
+}
+
+// selectLayer picks the layer titled with the asset file name when one is configured, or the only layer otherwise.
+func selectLayer(binary *Binary, manifest *oci.Manifest, platform, arch string) (*oci.Descriptor, error) {
+	if _, ok := binary.fileName[platform+","+arch]; ok {
+		fileName, err := binary.GetAssetFileName(platform, arch)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := range manifest.Layers {
+			if manifest.Layers[idx].Annotations[oci.AnnotationTitle] == fileName {
+				return &manifest.Layers[idx], nil
+			}
+		}
+
+		return nil, fmt.Errorf("manifest has no layer titled %q", fileName)
+	}
+
+	if len(manifest.Layers) != 1 {
+		return nil, fmt.Errorf("manifest has %d layers, configure fileName to select one by title", len(manifest.Layers))
+	}
+
+	return &manifest.Layers[0], nil
+}
+
+// layerFileName names a layer so that it is extracted according to its format.
+func layerFileName(binary *Binary, layer *oci.Descriptor) string {
+	if title := layer.Annotations[oci.AnnotationTitle]; title != "" {
+		return title
+	}
+
+	// Untitled layers of container images are gzipped tarballs
+	if strings.HasSuffix(layer.MediaType, "tar+gzip") {
+		return binary.Name + ".tar.gz"
+	}
+
+	return binary.Name
+}
+
+// verifyDigest checks that data matches a content digest such as "sha256:<hex>".
+func verifyDigest(digest string, data []byte) error {
+	algorithm, expected, _ := strings.Cut(digest, ":")
+	if algorithm != "sha256" {
+		return fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+
+	if expected != actual {
+		return fmt.Errorf("digest mismatch: expected %s, got sha256:%s", digest, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noizwaves/grab/pkg/oci"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ociDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestOCISource(t *testing.T) {
+	linuxLayer := []byte("linux binary")
+	darwinLayer := []byte("darwin binary")
+	tamperedDigest := ociDigest([]byte("something else"))
+	imageConfig := []byte(`{"created": "2024-02-01T12:00:00Z", "architecture": "amd64", "os": "linux"}`)
+
+	// An image index of per-platform manifests, as published for v1.2.0
+	linuxManifest := oci.Manifest{
+		MediaType: oci.MediaTypeImageManifest,
+		Config:    &oci.Descriptor{MediaType: oci.MediaTypeImageConfig, Digest: ociDigest(imageConfig)},
+		Layers: []oci.Descriptor{
+			{Digest: ociDigest(linuxLayer), Annotations: map[string]string{oci.AnnotationTitle: "tool"}},
+		},
+	}
+	linuxManifestData, _ := json.Marshal(linuxManifest)
+
+	index := oci.Manifest{
+		MediaType: oci.MediaTypeImageIndex,
+		Manifests: []oci.Descriptor{
+			{
+				MediaType: oci.MediaTypeImageManifest,
+				Digest:    ociDigest(linuxManifestData),
+				Platform:  &oci.Platform{OS: "linux", Architecture: "amd64"},
+			},
+		},
+	}
+
+	// A single manifest with a layer per platform, as pushed by ORAS for v1.1.0
+	annotated := oci.Manifest{
+		MediaType: oci.MediaTypeImageManifest,
+		Layers: []oci.Descriptor{
+			{Digest: ociDigest(linuxLayer), Annotations: map[string]string{oci.AnnotationTitle: "tool-linux-amd64"}},
+			{Digest: ociDigest(darwinLayer), Annotations: map[string]string{oci.AnnotationTitle: "tool-darwin-arm64"}},
+			{Digest: tamperedDigest, Annotations: map[string]string{oci.AnnotationTitle: "tool-linux-arm64"}},
+		},
+		Annotations: map[string]string{oci.AnnotationCreated: "2024-01-15T08:30:00Z"},
+	}
+
+	manifests := map[string]any{
+		"v1.2.0":                     index,
+		ociDigest(linuxManifestData): linuxManifest,
+		"v1.1.0":                     annotated,
+	}
+
+	blobs := map[string][]byte{
+		ociDigest(linuxLayer):  linuxLayer,
+		ociDigest(darwinLayer): darwinLayer,
+		tamperedDigest:         linuxLayer,
+		ociDigest(imageConfig): imageConfig,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		path := strings.TrimPrefix(request.URL.Path, "/v2/org/tool/")
+
+		if path == "tags/list" {
+			_, _ = responseWriter.Write([]byte(`{"tags": ["v1.1.0", "latest", "v1.10.0-rc.1", "v1.2.0", "v1.0.0"]}`))
+
+			return
+		}
+
+		if reference, ok := strings.CutPrefix(path, "manifests/"); ok && manifests[reference] != nil {
+			_ = json.NewEncoder(responseWriter).Encode(manifests[reference])
+
+			return
+		}
+
+		if digest, ok := strings.CutPrefix(path, "blobs/"); ok && blobs[digest] != nil {
+			_, _ = responseWriter.Write(blobs[digest])
+
+			return
+		}
+
+		responseWriter.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	base := Binary{
+		Name:         "tool",
+		SourceKind:   SourceKindOCI,
+		Repo:         "org/tool",
+		releaseName:  "v{{ .Version }}",
+		ReleaseRegex: regexp.MustCompile(`\d+\.\d+\.\d+`),
+	}
+
+	source := &OCISource{Client: oci.NewClientWithBaseURL(server.URL)}
+
+	t.Run("ListReleases", func(t *testing.T) {
+		releases, err := source.ListReleases(t.Context(), &base)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Release{
+			{Name: "v1.2.0", TagName: "v1.2.0"},
+			{Name: "v1.1.0", TagName: "v1.1.0"},
+			{Name: "v1.0.0", TagName: "v1.0.0"},
+		}, releases)
+	})
+
+	t.Run("LatestRelease", func(t *testing.T) {
+		release, err := source.GetLatestRelease(t.Context(), &base)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.2.0", release.TagName)
+
+		// Dated by the image config of a platform manifest
+		assert.Equal(t, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC), release.PublishedAt)
+	})
+
+	t.Run("CreatedAnnotation", func(t *testing.T) {
+		created, err := source.getCreated(t.Context(), &base, "v1.1.0")
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC), created)
+	})
+
+	t.Run("ImageIndex", func(t *testing.T) {
+		binary := base
+		binary.PinnedVersion = "1.2.0"
+
+		asset, err := source.ResolveAsset(t.Context(), &binary, "linux", "amd64")
+		require.NoError(t, err)
+		assert.Equal(t, &Asset{Name: "tool", Release: "v1.2.0", Digest: ociDigest(linuxLayer)}, asset)
+
+		data, err := source.DownloadAsset(t.Context(), &binary, asset)
+		require.NoError(t, err)
+		assert.Equal(t, linuxLayer, data)
+
+		_, err = source.ResolveAsset(t.Context(), &binary, "darwin", "arm64")
+		assert.EqualError(t, err, "image index has no manifest for darwin/arm64")
+	})
+
+	t.Run("LayerAnnotations", func(t *testing.T) {
+		binary := base
+		binary.PinnedVersion = "1.1.0"
+		binary.fileName = map[string]string{
+			"darwin,arm64": "tool-darwin-arm64",
+			"linux,arm64":  "tool-linux-arm64",
+		}
+
+		asset, err := source.ResolveAsset(t.Context(), &binary, "darwin", "arm64")
+		require.NoError(t, err)
+		assert.Equal(t, "tool-darwin-arm64", asset.Name)
+
+		data, err := source.DownloadAsset(t.Context(), &binary, asset)
+		require.NoError(t, err)
+		assert.Equal(t, darwinLayer, data)
+
+		// Layers are rejected when their content does not match the digest
+		asset, err = source.ResolveAsset(t.Context(), &binary, "linux", "arm64")
+		require.NoError(t, err)
+
+		_, err = source.DownloadAsset(t.Context(), &binary, asset)
+		assert.ErrorContains(t, err, "digest mismatch")
+	})
+
+	t.Run("MultipleLayersWithoutFileName", func(t *testing.T) {
+		binary := base
+		binary.PinnedVersion = "1.1.0"
+
+		_, err := source.ResolveAsset(t.Context(), &binary, "linux", "amd64")
+		assert.EqualError(t, err, "manifest has 3 layers, configure fileName to select one by title")
+	})
+}
@@ -0,0 +1,316 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/noizwaves/grab/pkg/github"
+	"github.com/noizwaves/grab/pkg/gitlab"
+	"github.com/noizwaves/grab/pkg/internal/githubh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourcesSourceFor(t *testing.T) {
+	var requestedHosts []string
+
+	sources := &Sources{
+		GitHubClient: &githubh.MockGitHubClient{},
+		NewGitHubEnterpriseClient: func(host string) github.Client {
+			requestedHosts = append(requestedHosts, host)
+
+			return &githubh.MockGitHubClient{}
+		},
+		NewGitLabClient: func(host string) gitlab.Client {
+			requestedHosts = append(requestedHosts, host)
+
+			return gitlab.NewClient(host, http.DefaultClient)
+		},
+	}
+
+	t.Run("GitHub", func(t *testing.T) {
+		source, err := sources.SourceFor(&Binary{Name: "foo", SourceKind: SourceKindGitHub})
+
+		assert.NoError(t, err)
+		assert.IsType(t, &GitHubSource{}, source)
+		assert.Empty(t, requestedHosts)
+	})
+
+	t.Run("GitHubEnterprise", func(t *testing.T) {
+		requestedHosts = nil
+
+		source, err := sources.SourceFor(&Binary{Name: "foo", SourceKind: SourceKindGitHub, Host: "ghe.corp"})
+
+		assert.NoError(t, err)
+		assert.IsType(t, &GitHubSource{}, source)
+		assert.Equal(t, []string{"ghe.corp"}, requestedHosts)
+	})
+
+	t.Run("GitLab", func(t *testing.T) {
+		requestedHosts = nil
+
+		source, err := sources.SourceFor(&Binary{Name: "foo", SourceKind: SourceKindGitLab, Host: "gitlab.corp"})
+
+		assert.NoError(t, err)
+		assert.IsType(t, &GitLabSource{}, source)
+		assert.Equal(t, []string{"gitlab.corp"}, requestedHosts)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := sources.SourceFor(&Binary{Name: "foo", SourceKind: "ftp"})
+
+		assert.EqualError(t, err, `unsupported source kind "ftp" for package "foo"`)
+	})
+}
+
+func TestGitHubSourceResolveAsset(t *testing.T) {
+	binary := &Binary{
+		Name:          "foo",
+		PinnedVersion: "1.2.3",
+		SourceKind:    SourceKindGitHub,
+		Org:           "bar",
+		Repo:          "foo",
+		releaseName:   "v{{ .Version }}",
+		fileName: map[string]string{
+			"linux,arm64": "foo-{{ .Version }}-linux-arm64.tar.gz",
+		},
+	}
+
+	source := &GitHubSource{Client: &githubh.MockGitHubClient{}}
+
+	asset, err := source.ResolveAsset(t.Context(), binary, "linux", "arm64")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Asset{Name: "foo-1.2.3-linux-arm64.tar.gz", Release: "v1.2.3"}, asset)
+}
+
+func TestGitHubSourceResolveAsset_Validation(t *testing.T) {
+	binary := &Binary{
+		Name:          "foo",
+		PinnedVersion: "1.2.3",
+		SourceKind:    SourceKindGitHub,
+		Org:           "bar",
+		Repo:          "foo",
+		releaseName:   "v{{ .Version }}",
+		fileName: map[string]string{
+			"linux,arm64": "foo-{{ .Version }}-linux-arm64.tar.gz",
+		},
+	}
+
+	t.Run("Found", func(t *testing.T) {
+		client := &githubh.MockGitHubClient{
+			Release: &github.Release{TagName: "v1.2.3", Assets: []github.Asset{
+				{ID: 42, Name: "foo-1.2.3-linux-arm64.tar.gz", Size: 2048},
+			}},
+			AssetData: []byte("binary"),
+		}
+		source := &GitHubSource{Client: client}
+
+		asset, err := source.ResolveAsset(t.Context(), binary, "linux", "arm64")
+
+		require.NoError(t, err)
+		assert.Equal(t, &Asset{Name: "foo-1.2.3-linux-arm64.tar.gz", Release: "v1.2.3", Size: 2048, ID: 42}, asset)
+
+		// The download reuses the asset ID in place of looking up the release again
+		_, err = source.DownloadAsset(t.Context(), binary, asset)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int64{42}, client.DownloadReleaseAssetByIDCalls)
+		assert.Equal(t, []githubh.GetReleaseByTagCall{{Org: "bar", Repo: "foo", Tag: "v1.2.3"}}, client.GetReleaseByTagCalls)
+	})
+
+	t.Run("AssetMissing", func(t *testing.T) {
+		source := &GitHubSource{Client: &githubh.MockGitHubClient{
+			Release: &github.Release{TagName: "v1.2.3", Assets: []github.Asset{
+				{Name: "foo-1.2.3-darwin-arm64.tar.gz"},
+				{Name: "foo-1.2.3-linux-aarch64.tar.gz"},
+				{Name: "foo-1.2.3-linux-aarch64.tar.gz.sig"},
+			}},
+		}}
+
+		_, err := source.ResolveAsset(t.Context(), binary, "linux", "arm64")
+
+		assert.EqualError(t, err, `asset "foo-1.2.3-linux-arm64.tar.gz" not found in release "v1.2.3"; `+
+			`did you mean "foo-1.2.3-linux-aarch64.tar.gz" or "foo-1.2.3-darwin-arm64.tar.gz"? `+
+			`Fix the fileName entry for "linux,arm64" of foo`)
+	})
+
+	t.Run("OnlyChecksums", func(t *testing.T) {
+		source := &GitHubSource{Client: &githubh.MockGitHubClient{
+			Release: &github.Release{TagName: "v1.2.3", Assets: []github.Asset{
+				{Name: "foo-1.2.3-linux-aarch64.tar.gz.sig"},
+				{Name: "checksums.sha256"},
+			}},
+		}}
+
+		_, err := source.ResolveAsset(t.Context(), binary, "linux", "arm64")
+
+		assert.EqualError(t, err, `asset "foo-1.2.3-linux-arm64.tar.gz" not found in release "v1.2.3"; `+
+			"available assets: foo-1.2.3-linux-aarch64.tar.gz.sig, checksums.sha256")
+	})
+
+	t.Run("ReleaseMissing", func(t *testing.T) {
+		source := &GitHubSource{Client: &releaseNotFoundClient{}}
+
+		_, err := source.ResolveAsset(t.Context(), binary, "linux", "arm64")
+
+		require.ErrorIs(t, err, github.ErrNotFound)
+		assert.EqualError(t, err, `release "v1.2.3" not found in bar/foo; check the release name of foo (Not Found)`)
+	})
+}
+
+func TestGitHubSourceDownloadAsset_Errors(t *testing.T) {
+	binary := &Binary{Name: "foo", Org: "bar", Repo: "foo"}
+	asset := &Asset{Name: "foo-1.2.3-linux-arm64.tar.gz", Release: "v1.2.3"}
+	notFound := &github.APIError{StatusCode: http.StatusNotFound, Message: "Not Found"}
+
+	t.Run("AssetNotFound", func(t *testing.T) {
+		source := &GitHubSource{Client: &githubh.MockGitHubClient{
+			AssetError: notFound,
+			Release: &github.Release{Assets: []github.Asset{
+				{Name: "foo-1.2.3-linux-aarch64.tar.gz"},
+				{Name: "foo-1.2.3-linux-x86_64.tar.gz"},
+			}},
+		}}
+
+		_, err := source.DownloadAsset(t.Context(), binary, asset)
+
+		require.ErrorIs(t, err, github.ErrNotFound)
+		assert.EqualError(t, err, `asset "foo-1.2.3-linux-arm64.tar.gz" not found in release "v1.2.3" (Not Found); `+
+			"available assets: foo-1.2.3-linux-aarch64.tar.gz, foo-1.2.3-linux-x86_64.tar.gz")
+	})
+
+	t.Run("ReleaseNotFound", func(t *testing.T) {
+		source := &GitHubSource{Client: &releaseNotFoundClient{MockGitHubClient: githubh.MockGitHubClient{
+			AssetError: notFound,
+		}}}
+
+		_, err := source.DownloadAsset(t.Context(), binary, asset)
+
+		assert.EqualError(t, err, `release "v1.2.3" not found in bar/foo (Not Found)`)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		source := &GitHubSource{Client: &githubh.MockGitHubClient{
+			AssetError: &github.APIError{StatusCode: http.StatusUnauthorized, Message: "Bad credentials"},
+		}}
+
+		_, err := source.DownloadAsset(t.Context(), binary, asset)
+
+		require.ErrorIs(t, err, github.ErrUnauthorized)
+		assert.ErrorContains(t, err, "access to bar/foo was denied")
+	})
+}
+
+type releaseNotFoundClient struct {
+	githubh.MockGitHubClient
+}
+
+func (c *releaseNotFoundClient) GetReleaseByTag(_ context.Context, _, _, _ string) (*github.Release, error) {
+	return nil, &github.APIError{StatusCode: http.StatusNotFound, Message: "Not Found"}
+}
+
+func TestConfigPackageSpecSourceKind(t *testing.T) {
+	t.Run("GitHub", func(t *testing.T) {
+		spec := ConfigPackageSpec{GitHubRelease: &ConfigGitHubRelease{}}
+
+		kind, err := spec.SourceKind()
+
+		assert.NoError(t, err)
+		assert.Equal(t, SourceKindGitHub, kind)
+	})
+
+	t.Run("GitLab", func(t *testing.T) {
+		spec := ConfigPackageSpec{GitLabRelease: &ConfigGitLabRelease{}}
+
+		kind, err := spec.SourceKind()
+
+		assert.NoError(t, err)
+		assert.Equal(t, SourceKindGitLab, kind)
+	})
+
+	t.Run("MultipleSources", func(t *testing.T) {
+		spec := ConfigPackageSpec{
+			GitHubRelease: &ConfigGitHubRelease{},
+			GitLabRelease: &ConfigGitLabRelease{},
+		}
+
+		_, err := spec.SourceKind()
+
+		assert.EqualError(t, err, "multiple sources configured: github, gitlab")
+	})
+
+	t.Run("NoSource", func(t *testing.T) {
+		spec := ConfigPackageSpec{}
+
+		_, err := spec.SourceKind()
+
+		assert.EqualError(t, err, "no source configured")
+	})
+}
+
+func TestGitLabSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		switch request.URL.EscapedPath() {
+		case "/projects/gitlab-org%2Fcli/releases/permalink/latest":
+			_ = json.NewEncoder(responseWriter).Encode(gitlab.Release{
+				Name:        "v1.40.0",
+				TagName:     "v1.40.0",
+				Description: "Bug fixes",
+				Links:       gitlab.ReleaseLinks{Self: "https://gitlab.com/gitlab-org/cli/-/releases/v1.40.0"},
+			})
+		case "/projects/gitlab-org%2Fcli/releases/v1.40.0":
+			_ = json.NewEncoder(responseWriter).Encode(gitlab.Release{
+				TagName: "v1.40.0",
+				Assets: gitlab.Assets{
+					Links: []gitlab.AssetLink{{Name: "glab_1.40.0_linux_amd64", URL: "http://" + request.Host + "/asset"}},
+				},
+			})
+		case "/asset":
+			_, _ = responseWriter.Write([]byte("binary"))
+		default:
+			responseWriter.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	binary := &Binary{
+		Name:          "glab",
+		PinnedVersion: "1.40.0",
+		SourceKind:    SourceKindGitLab,
+		Project:       "gitlab-org/cli",
+		releaseName:   "v{{ .Version }}",
+		fileName: map[string]string{
+			"linux,amd64": "glab_{{ .Version }}_linux_amd64",
+		},
+	}
+
+	source := &GitLabSource{Client: gitlab.NewClientWithBaseURL(server.URL)}
+
+	latest, err := source.GetLatestRelease(t.Context(), binary)
+	require.NoError(t, err)
+	assert.Equal(t, &Release{
+		Name:    "v1.40.0",
+		TagName: "v1.40.0",
+		URL:     "https://gitlab.com/gitlab-org/cli/-/releases/v1.40.0",
+		Body:    "Bug fixes",
+	}, latest)
+
+	asset, err := source.ResolveAsset(t.Context(), binary, "linux", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, &Asset{Name: "glab_1.40.0_linux_amd64", Release: "v1.40.0"}, asset)
+
+	data, err := source.DownloadAsset(t.Context(), binary, asset)
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(data))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("1.2.3", "1.2.3"))
+	assert.Equal(t, 1, compareVersions("1.10.0", "1.9.0"))
+	assert.Equal(t, -1, compareVersions("1.2", "1.2.1"))
+	assert.Equal(t, -1, compareVersions("2024.01.a", "2024.01.b"))
+}
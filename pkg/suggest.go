@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxAssetSuggestions is the number of similarly named assets suggested when an asset is missing.
+const maxAssetSuggestions = 3
+
+// platformAliases are the names releases commonly use for each platform.
+var platformAliases = map[string][]string{
+	"linux":   {"linux"},
+	"darwin":  {"darwin", "macos", "osx", "apple"},
+	"freebsd": {"freebsd"},
+}
+
+// archAliases are the names releases commonly use for each architecture.
+var archAliases = map[string][]string{
+	"amd64":   {"amd64", "x86_64", "x64"},
+	"arm64":   {"arm64", "aarch64"},
+	"386":     {"386", "i686"},
+	"arm":     {"armv7", "armhf"},
+	"riscv64": {"riscv64"},
+	"s390x":   {"s390x"},
+}
+
+// nonExecutableSuffixes mark assets published alongside executables, which are never the asset intended.
+var nonExecutableSuffixes = []string{".sha256", ".sha256sum", ".sha512", ".sig", ".asc", ".pem", ".sbom", ".json"}
+
+// suggestAssetNames returns the assets most likely intended in place of want, preferring those naming the
+// platform and architecture, and then those with the most similar names.
+func suggestAssetNames(want string, candidates []string, platform, arch string) []string {
+	type scored struct {
+		name     string
+		tokens   int
+		distance int
+	}
+
+	scores := make([]scored, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+
+		if slices.ContainsFunc(nonExecutableSuffixes, func(suffix string) bool {
+			return strings.HasSuffix(lower, suffix)
+		}) {
+			continue
+		}
+
+		tokens := 0
+		if containsAny(lower, platformAliases[platform]) {
+			tokens++
+		}
+
+		if containsAny(lower, archAliases[arch]) {
+			tokens++
+		}
+
+		scores = append(scores, scored{
+			name:     candidate,
+			tokens:   tokens,
+			distance: editDistance(strings.ToLower(want), lower),
+		})
+	}
+
+	slices.SortStableFunc(scores, func(a, b scored) int {
+		if a.tokens != b.tokens {
+			return b.tokens - a.tokens
+		}
+
+		return a.distance - b.distance
+	})
+
+	output := make([]string, 0, maxAssetSuggestions)
+	for _, score := range scores[:min(len(scores), maxAssetSuggestions)] {
+		output = append(output, score.name)
+	}
+
+	return output
+}
+
+func containsAny(value string, substrings []string) bool {
+	return slices.ContainsFunc(substrings, func(substring string) bool {
+		return strings.Contains(value, substring)
+	})
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("fzf", "fzf"))
+	assert.Equal(t, 3, editDistance("", "fzf"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 2, editDistance("fzf-linux-arm64", "fzf-linux-amd64"))
+}
+
+func TestSuggestAssetNames(t *testing.T) {
+	candidates := []string{
+		"fzf-0.45.0-darwin_amd64.tar.gz",
+		"fzf-0.45.0-darwin_arm64.tar.gz",
+		"fzf-0.45.0-linux_aarch64.tar.gz",
+		"fzf-0.45.0-linux_aarch64.tar.gz.sha256",
+		"fzf-0.45.0-linux_x86_64.tar.gz",
+		"fzf_0.45.0_checksums.txt",
+	}
+
+	t.Run("PlatformAndArchitectureAliases", func(t *testing.T) {
+		suggestions := suggestAssetNames("fzf-0.45.0-linux_arm64.tar.gz", candidates, "linux", "arm64")
+
+		assert.Equal(t, []string{
+			"fzf-0.45.0-linux_aarch64.tar.gz",
+			"fzf-0.45.0-linux_x86_64.tar.gz",
+			"fzf-0.45.0-darwin_arm64.tar.gz",
+		}, suggestions)
+	})
+
+	t.Run("EditDistance", func(t *testing.T) {
+		suggestions := suggestAssetNames("fzf-0.45.0-darwin-amd64.tar.gz", candidates, "darwin", "amd64")
+
+		assert.Equal(t, "fzf-0.45.0-darwin_amd64.tar.gz", suggestions[0])
+	})
+
+	t.Run("NoCandidates", func(t *testing.T) {
+		assert.Empty(t, suggestAssetNames("fzf.tar.gz", []string{"fzf.tar.gz.sig"}, "linux", "amd64"))
+	})
+}
@@ -0,0 +1,150 @@
+// Package transport builds the HTTP client shared by every source, so that certificates, timeouts and proxies
+// are configured in one place.
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	DefaultConnectTimeout = 30 * time.Second
+	DefaultReadTimeout    = 60 * time.Second
+)
+
+// Config describes how connections to servers are made.
+type Config struct {
+	// CABundles are PEM files of certificate authorities trusted in addition to the system's,
+	// such as that of a TLS-intercepting proxy
+	CABundles []string
+
+	// ClientCert and ClientKey are PEM files presenting a client certificate, for mutual TLS
+	ClientCert string
+	ClientKey  string
+
+	// ConnectTimeout bounds establishing a connection, including the TLS handshake
+	ConnectTimeout time.Duration
+
+	// ReadTimeout bounds waiting for data from the server, so that a stalled response fails rather than hangs
+	ReadTimeout time.Duration
+
+	// Proxy is the URL of the proxy to use for all requests (default: HTTPS_PROXY, HTTP_PROXY and NO_PROXY)
+	Proxy string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		ConnectTimeout: DefaultConnectTimeout,
+		ReadTimeout:    DefaultReadTimeout,
+	}
+}
+
+// NewClient creates an HTTP client for config.
+func NewClient(config Config) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", config.Proxy)
+		}
+
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   config.ConnectTimeout,
+		KeepAlive: 30 * time.Second, //nolint:mnd
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+	transport.TLSHandshakeTimeout = config.ConnectTimeout
+	transport.ResponseHeaderTimeout = config.ReadTimeout
+	transport.DialContext = dialer.DialContext
+
+	if config.ReadTimeout > 0 {
+		transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, address)
+			if err != nil {
+				return nil, err //nolint:wrapcheck
+			}
+
+			return &readTimeoutConn{Conn: conn, timeout: config.ReadTimeout}, nil
+		}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+func newTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if len(config.CABundles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		for _, bundle := range config.CABundles {
+			data, err := os.ReadFile(bundle)
+			if err != nil {
+				return nil, fmt.Errorf("error reading CA bundle: %w", err)
+			}
+
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("no certificates found in CA bundle %s", bundle)
+			}
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if (config.ClientCert == "") != (config.ClientKey == "") {
+		return nil, errors.New("a client certificate and key must be configured together")
+	}
+
+	if config.ClientCert != "" {
+		certificate, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// readTimeoutConn fails reads that wait longer than timeout for data.
+// Unlike a deadline for the whole request, large downloads succeed as long as data keeps arriving.
+type readTimeoutConn struct {
+	net.Conn
+
+	timeout time.Duration
+}
+
+func (c *readTimeoutConn) Read(data []byte) (int, error) {
+	err := c.SetReadDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return c.Conn.Read(data) //nolint:wrapcheck
+}
@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writePEM(t *testing.T, blockType string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), strings.ToLower(strings.ReplaceAll(blockType, " ", "-"))+".pem")
+
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// writeClientCertificate generates a self-signed client certificate, returning the paths of the certificate and key.
+func writeClientCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "grab"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyData, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return writePEM(t, "CERTIFICATE", certificate), writePEM(t, "PRIVATE KEY", keyData)
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	return resp, err
+}
+
+func TestNewClient_CABundles(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	client, err := NewClient(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = get(t, client, server.URL)
+	if err == nil {
+		t.Error("Expected the server's certificate to be untrusted by default")
+	}
+
+	config := DefaultConfig()
+	config.CABundles = []string{writePEM(t, "CERTIFICATE", server.Certificate().Raw)}
+
+	client, err = NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = get(t, client, server.URL)
+	if err != nil {
+		t.Errorf("Expected the CA bundle to be trusted, got %v", err)
+	}
+}
+
+func TestNewClient_ClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if len(request.TLS.PeerCertificates) == 0 {
+			responseWriter.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert} //nolint:gosec
+	server.StartTLS()
+
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.CABundles = []string{writePEM(t, "CERTIFICATE", server.Certificate().Raw)}
+	config.ClientCert, config.ClientKey = writeClientCertificate(t)
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := get(t, client, server.URL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the client certificate to be presented, got status %s", resp.Status)
+	}
+}
+
+func TestNewClient_ReadTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		responseWriter.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.ReadTimeout = 50 * time.Millisecond
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = get(t, client, server.URL)
+	if err == nil {
+		t.Error("Expected a stalled response to time out")
+	}
+}
+
+func TestNewClient_Proxy(t *testing.T) {
+	proxied := ""
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+		proxied = request.URL.String()
+	}))
+	defer proxy.Close()
+
+	config := DefaultConfig()
+	config.Proxy = proxy.URL
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = get(t, client, "http://releases.example.com/file.tar.gz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if proxied != "http://releases.example.com/file.tar.gz" {
+		t.Errorf("Expected the request to be sent through the proxy, got '%s'", proxied)
+	}
+}
+
+func TestNewClient_InvalidConfig(t *testing.T) {
+	certPath, _ := writeClientCertificate(t)
+
+	cases := map[string]Config{
+		"missing CA bundle":   {CABundles: []string{filepath.Join(t.TempDir(), "missing.pem")}},
+		"empty CA bundle":     {CABundles: []string{writePEM(t, "NOTHING", nil)}},
+		"certificate only":    {ClientCert: certPath},
+		"unparseable proxy":   {Proxy: "://proxy"},
+		"mismatched key pair": {ClientCert: certPath, ClientKey: certPath},
+	}
+
+	for name, config := range cases {
+		_, err := NewClient(config)
+		if err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
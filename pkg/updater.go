@@ -2,59 +2,64 @@ package pkg
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-
-	"github.com/noizwaves/grab/pkg/github"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
 )
 
 type Updater struct {
-	GitHubClient github.Client
+	Sources *Sources
+
+	// ShowChangelog prints the notes of every release between the pinned and latest versions.
+	ShowChangelog bool
 }
 
-func (u *Updater) Update(gCtx *GrabContext, packageName string, out io.Writer) error {
-	ctx := context.Background()
-	// Validate package name if specified
-	if packageName != "" {
-		if _, exists := gCtx.Config.Packages[packageName]; !exists {
-			return fmt.Errorf("package %q not found in configuration", packageName)
-		}
+func (u *Updater) Update(ctx context.Context, gCtx *GrabContext, packageName string, out io.Writer) error {
+	err := validatePackageSelection(gCtx, packageName)
+	if err != nil {
+		return err
+	}
 
+	if packageName != "" {
 		slog.InfoContext(ctx, "Updating specific package", "package", packageName)
 	} else {
-		// Check if any packages are configured
-		if len(gCtx.Config.Packages) == 0 {
-			return fmt.Errorf("no packages configured in %s", gCtx.ConfigPath)
-		}
-
 		slog.InfoContext(ctx, "Updating all configured packages")
 	}
 
 	dirty := false
 
 	binariesToProcess := u.filterBinaries(gCtx.Binaries, packageName)
+	latestReleases := u.prefetchLatestReleases(ctx, binariesToProcess)
 
 	for _, binary := range binariesToProcess {
-		latestRelease, err := u.GitHubClient.GetLatestRelease(binary.Org, binary.Repo)
+		status, err := u.checkBinary(ctx, binary, latestReleases[binary])
 		if err != nil {
-			return fmt.Errorf("error fetching latest release for package %q: %w", binary.Name, err)
+			return err
 		}
 
-		latestVersion, err := extractReleaseVersion(binary, latestRelease)
-		if err != nil {
-			return fmt.Errorf("error extracting version for package %q: %w", binary.Name, err)
+		writeStatusText(status, out)
+
+		if status.Status != StatusOutdated {
+			continue
 		}
 
-		if latestVersion == binary.PinnedVersion {
-			fmt.Fprintf(out, "%s: %s is latest\n", binary.Name, binary.PinnedVersion)
-		} else {
-			fmt.Fprintf(out, "%s: %s -> %s (%s)\n", binary.Name, binary.PinnedVersion, latestVersion, latestRelease.URL)
+		if u.ShowChangelog {
+			// The changelog is informational, so the update is still applied without it
+			err := u.printChangelog(ctx, binary, status.LatestVersion, out)
+			if err != nil {
+				slog.WarnContext(ctx, "Unable to fetch changelog", "package", binary.Name, "error", err)
+			}
+		}
 
-			dirty = true
+		dirty = true
 
-			setBinaryVersion(gCtx.Config, binary.Name, latestVersion)
-		}
+		setBinaryVersion(gCtx.Config, binary.Name, status.LatestVersion)
 	}
 
 	if dirty {
@@ -71,6 +76,172 @@ func (u *Updater) Update(gCtx *GrabContext, packageName string, out io.Writer) e
 	return nil
 }
 
+// Check compares configured packages against their latest releases without modifying the config.
+// Results are sorted by package name.
+func (u *Updater) Check(ctx context.Context, gCtx *GrabContext, packageName string) ([]PackageStatus, error) {
+	err := validatePackageSelection(gCtx, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Checking packages for newer releases", "package", packageName)
+
+	binariesToProcess := u.filterBinaries(gCtx.Binaries, packageName)
+	statuses := make([]PackageStatus, 0, len(binariesToProcess))
+	latestReleases := u.prefetchLatestReleases(ctx, binariesToProcess)
+
+	for _, binary := range binariesToProcess {
+		status, err := u.checkBinary(ctx, binary, latestReleases[binary])
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, *status)
+	}
+
+	slices.SortFunc(statuses, func(a, b PackageStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses, nil
+}
+
+// prefetchLatestReleases looks up the latest releases of binaries sharing a source in batches, where supported.
+// Failures are not fatal, as the binaries are then looked up individually.
+func (u *Updater) prefetchLatestReleases(ctx context.Context, binaries []*Binary) map[*Binary]*Release {
+	groups := make(map[string][]*Binary)
+	for _, binary := range binaries {
+		key := binary.SourceKind + " " + binary.Host
+		groups[key] = append(groups[key], binary)
+	}
+
+	output := make(map[*Binary]*Release, len(binaries))
+
+	for _, group := range groups {
+		// A batch of one saves nothing
+		if len(group) == 1 {
+			continue
+		}
+
+		source, err := u.Sources.SourceFor(group[0])
+		if err != nil {
+			continue
+		}
+
+		batchSource, ok := source.(BatchSource)
+		if !ok {
+			continue
+		}
+
+		releases, err := batchSource.GetLatestReleases(ctx, group)
+		if err != nil {
+			slog.DebugContext(ctx, "Batch lookup of latest releases failed, looking up individually", "error", err)
+
+			continue
+		}
+
+		maps.Copy(output, releases)
+	}
+
+	return output
+}
+
+// checkBinary compares a binary's pinned version against its latest release, which is looked up when not prefetched.
+func (u *Updater) checkBinary(ctx context.Context, binary *Binary, latestRelease *Release) (*PackageStatus, error) {
+	if latestRelease == nil {
+		source, err := u.Sources.SourceFor(binary)
+		if err != nil {
+			return nil, err
+		}
+
+		latestRelease, err = source.GetLatestRelease(ctx, binary)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching latest release for package %q: %w", binary.Name, err)
+		}
+	}
+
+	latestVersion, err := extractReleaseVersion(binary, latestRelease)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting version for package %q: %w", binary.Name, err)
+	}
+
+	status := &PackageStatus{
+		Name:           binary.Name,
+		CurrentVersion: binary.PinnedVersion,
+		LatestVersion:  latestVersion,
+		URL:            latestRelease.URL,
+	}
+
+	remaining := releaseAgeRemaining(binary, latestRelease, time.Now())
+
+	switch {
+	case latestVersion == binary.PinnedVersion:
+		status.Status = StatusCurrent
+	case remaining > 0 && latestRelease.PublishedAt.IsZero():
+		status.Status = StatusPending
+
+		slog.WarnContext(ctx, "Holding back release of unknown age, set the package's minReleaseAge to 0s to adopt it",
+			"package", binary.Name, "version", latestVersion)
+	case remaining > 0:
+		status.Status = StatusPending
+		status.AvailableInHours = int(math.Ceil(remaining.Hours()))
+	default:
+		status.Status = StatusOutdated
+	}
+
+	return status, nil
+}
+
+func (u *Updater) printChangelog(ctx context.Context, binary *Binary, latestVersion string, out io.Writer) error {
+	source, err := u.Sources.SourceFor(binary)
+	if err != nil {
+		return err
+	}
+
+	releases, err := source.ListReleases(ctx, binary)
+	if errors.Is(err, ErrListingUnsupported) {
+		slog.DebugContext(ctx, "Skipping changelog, releases cannot be listed", "package", binary.Name)
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error listing releases: %w", err)
+	}
+
+	between, complete := releasesBetween(binary, releases, binary.PinnedVersion, latestVersion)
+
+	for _, release := range between {
+		fmt.Fprintf(out, "\n  %s", release.Name)
+
+		if !release.PublishedAt.IsZero() {
+			fmt.Fprintf(out, " (%s)", release.PublishedAt.Format(time.DateOnly))
+		}
+
+		fmt.Fprintf(out, "\n%s\n\n", renderReleaseNotes(release.Body))
+	}
+
+	if len(between) > 0 && !complete {
+		fmt.Fprintf(out, "  ... earlier releases since %s not shown\n\n", binary.PinnedVersion)
+	}
+
+	return nil
+}
+
+func validatePackageSelection(gCtx *GrabContext, packageName string) error {
+	if packageName != "" {
+		if _, exists := gCtx.Config.Packages[packageName]; !exists {
+			return fmt.Errorf("package %q not found in configuration", packageName)
+		}
+
+		return nil
+	}
+
+	if len(gCtx.Config.Packages) == 0 {
+		return fmt.Errorf("no packages configured in %s", gCtx.ConfigPath)
+	}
+
+	return nil
+}
+
 func (u *Updater) filterBinaries(binaries []*Binary, packageName string) []*Binary {
 	if packageName == "" {
 		return binaries
@@ -85,7 +256,22 @@ func (u *Updater) filterBinaries(binaries []*Binary, packageName string) []*Bina
 	return []*Binary{}
 }
 
-func extractReleaseVersion(binary *Binary, release *github.Release) (string, error) {
+// releaseAgeRemaining returns how long until a release satisfies the binary's minimum release age.
+// Releases without a publication time, such as those of http sources, are treated as just published,
+// so that the policy holds them back rather than letting releases of unknown age through.
+func releaseAgeRemaining(binary *Binary, release *Release, now time.Time) time.Duration {
+	if binary.MinReleaseAge == 0 {
+		return 0
+	}
+
+	if release.PublishedAt.IsZero() {
+		return binary.MinReleaseAge
+	}
+
+	return binary.MinReleaseAge - now.Sub(release.PublishedAt)
+}
+
+func extractReleaseVersion(binary *Binary, release *Release) (string, error) {
 	matches := binary.ReleaseRegex.FindStringSubmatch(release.Name)
 	if len(matches) == 0 {
 		return "", fmt.Errorf("release regex did not match name %q", release.Name)
@@ -2,8 +2,12 @@ package pkg
 
 import (
 	"bytes"
+	"context"
+	"net/http"
 	"path"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/noizwaves/grab/pkg/github"
 	"github.com/noizwaves/grab/pkg/internal/asserth"
@@ -22,16 +26,18 @@ func TestUpdate(t *testing.T) {
 	}
 
 	updater := Updater{
-		GitHubClient: &githubh.MockGitHubClient{
-			Release: &github.Release{
-				Name: "2.0.0",
-				URL:  "https://fakegithub.com/release-information",
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "2.0.0",
+					URL:  "https://fakegithub.com/release-information",
+				},
 			},
 		},
 	}
 
 	out := &bytes.Buffer{}
-	err = updater.Update(gCtx, "", out)
+	err = updater.Update(t.Context(), gCtx, "", out)
 
 	assert.NoError(t, err)
 
@@ -53,16 +59,18 @@ func TestUpdateValidPackageName(t *testing.T) {
 	}
 
 	updater := Updater{
-		GitHubClient: &githubh.MockGitHubClient{
-			Release: &github.Release{
-				Name: "2.0.0",
-				URL:  "https://fakegithub.com/release-information",
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "2.0.0",
+					URL:  "https://fakegithub.com/release-information",
+				},
 			},
 		},
 	}
 
 	out := &bytes.Buffer{}
-	err = updater.Update(gCtx, "bar", out)
+	err = updater.Update(t.Context(), gCtx, "bar", out)
 
 	assert.NoError(t, err)
 
@@ -82,16 +90,18 @@ func TestUpdateInvalidPackageName(t *testing.T) {
 	}
 
 	updater := Updater{
-		GitHubClient: &githubh.MockGitHubClient{
-			Release: &github.Release{
-				Name: "2.0.0",
-				URL:  "https://fakegithub.com/release-information",
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "2.0.0",
+					URL:  "https://fakegithub.com/release-information",
+				},
 			},
 		},
 	}
 
 	out := &bytes.Buffer{}
-	err = updater.Update(gCtx, "nonexistent", out)
+	err = updater.Update(t.Context(), gCtx, "nonexistent", out)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), `package "nonexistent" not found in configuration`)
@@ -114,11 +124,13 @@ func TestUpdateSinglePackageIgnoresOthers(t *testing.T) {
 	}
 
 	updater := Updater{
-		GitHubClient: mockClient,
+		Sources: &Sources{
+			GitHubClient: mockClient,
+		},
 	}
 
 	out := &bytes.Buffer{}
-	err = updater.Update(gCtx, "bar", out)
+	err = updater.Update(t.Context(), gCtx, "bar", out)
 
 	assert.NoError(t, err)
 
@@ -145,16 +157,18 @@ func TestUpdatePackageAlreadyLatest(t *testing.T) {
 	}
 
 	updater := Updater{
-		GitHubClient: &githubh.MockGitHubClient{
-			Release: &github.Release{
-				Name: "1.0.0", // Same version as in config
-				URL:  "https://fakegithub.com/release-information",
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "1.0.0", // Same version as in config
+					URL:  "https://fakegithub.com/release-information",
+				},
 			},
 		},
 	}
 
 	out := &bytes.Buffer{}
-	err = updater.Update(gCtx, "bar", out)
+	err = updater.Update(t.Context(), gCtx, "bar", out)
 
 	assert.NoError(t, err)
 
@@ -176,17 +190,311 @@ func TestUpdateNoPackagesConfigured(t *testing.T) {
 	}
 
 	updater := Updater{
-		GitHubClient: &githubh.MockGitHubClient{
-			Release: &github.Release{
-				Name: "2.0.0",
-				URL:  "https://fakegithub.com/release-information",
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "2.0.0",
+					URL:  "https://fakegithub.com/release-information",
+				},
 			},
 		},
 	}
 
 	out := &bytes.Buffer{}
-	err = updater.Update(gCtx, "", out)
+	err = updater.Update(t.Context(), gCtx, "", out)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "no packages configured")
 }
+
+// Test that release notes between the pinned and latest versions are printed.
+func TestUpdateWithChangelog(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+
+	gCtx, err := NewGrabContext(configDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockClient := &githubh.MockGitHubClient{
+		Release: &github.Release{
+			Name: "2.0.0",
+			URL:  "https://fakegithub.com/release-information",
+		},
+		Releases: []github.Release{
+			{Name: "2.1.0-rc1", Body: "Unreleased notes", Prerelease: true},
+			{Name: "2.0.0", Body: "## Breaking\n* Removed foo", PublishedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{Name: "1.1.0", Body: "Added bar"},
+			{Name: "1.0.0", Body: "Initial release"},
+		},
+	}
+
+	updater := Updater{
+		Sources: &Sources{
+			GitHubClient: mockClient,
+		},
+		ShowChangelog: true,
+	}
+
+	out := &bytes.Buffer{}
+	err = updater.Update(t.Context(), gCtx, "", out)
+
+	assert.NoError(t, err)
+	assert.Len(t, mockClient.ListReleasesCalls, 1)
+
+	output := out.String()
+	assert.Contains(t, output, "bar: 1.0.0 -> 2.0.0 (https://fakegithub.com/release-information)")
+	assert.Contains(t, output, "  2.0.0 (2024-02-01)\n    Breaking\n    - Removed foo\n")
+	assert.Contains(t, output, "  1.1.0\n    Added bar\n")
+	assert.NotContains(t, output, "Unreleased notes")
+	assert.NotContains(t, output, "Initial release")
+	assert.NotContains(t, output, "not shown")
+}
+
+// Test that the changelog is marked as incomplete when the pinned version is older than the listed releases.
+func TestUpdateWithChangelog_PinnedVersionNotListed(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+
+	gCtx, err := NewGrabContext(configDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updater := Updater{
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "2.0.0",
+					URL:  "https://fakegithub.com/release-information",
+				},
+				Releases: []github.Release{
+					{Name: "2.0.0", Body: "Removed foo"},
+					{Name: "1.9.0", Body: "Added bar"},
+				},
+			},
+		},
+		ShowChangelog: true,
+	}
+
+	out := &bytes.Buffer{}
+	err = updater.Update(t.Context(), gCtx, "", out)
+
+	assert.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "  2.0.0\n    Removed foo\n")
+	assert.Contains(t, output, "  1.9.0\n    Added bar\n\n  ... earlier releases since 1.0.0 not shown\n")
+}
+
+// Test that the update is applied when release notes cannot be fetched.
+func TestUpdateWithChangelog_ListingFails(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/simple")
+
+	gCtx, err := NewGrabContext(configDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updater := Updater{
+		Sources: &Sources{
+			GitHubClient: &listReleasesFailingClient{MockGitHubClient: githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "2.0.0",
+					URL:  "https://fakegithub.com/release-information",
+				},
+			}},
+		},
+		ShowChangelog: true,
+	}
+
+	out := &bytes.Buffer{}
+	err = updater.Update(t.Context(), gCtx, "", out)
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Updated config file. Now run `grab install`.")
+
+	asserth.FileContents(t, path.Join(configDir, "config.yml"), "packages:\n  bar: 2.0.0\n")
+}
+
+type listReleasesFailingClient struct {
+	githubh.MockGitHubClient
+}
+
+func (c *listReleasesFailingClient) ListReleases(_ context.Context, _, _ string) ([]github.Release, error) {
+	return nil, &github.APIError{StatusCode: http.StatusBadGateway, Message: "Bad Gateway"}
+}
+
+// Test that releases younger than the minimum release age are reported but not applied.
+func TestUpdateMinReleaseAge(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/min-release-age")
+
+	gCtx, err := NewGrabContext(configDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updater := Updater{
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name:        "2.0.0",
+					URL:         "https://fakegithub.com/release-information",
+					PublishedAt: time.Now().Add(-2 * time.Hour),
+				},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	err = updater.Update(t.Context(), gCtx, "", out)
+
+	assert.NoError(t, err)
+
+	// bar inherits the global 72h policy, baz overrides it with 1h
+	output := out.String()
+	assert.Contains(t, output, "bar: 1.0.0 -> 2.0.0 available in 70 hours (https://fakegithub.com/release-information)")
+	assert.Contains(t, output, "baz: 1.2.3 -> 2.0.0 (https://fakegithub.com/release-information)")
+
+	asserth.FileContents(t, path.Join(configDir, "config.yml"),
+		"packages:\n  bar: 1.0.0\n  baz: 2.0.0\nsettings:\n  minReleaseAge: 72h\n")
+}
+
+func TestReleaseAgeRemaining(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	binary := &Binary{MinReleaseAge: 72 * time.Hour}
+
+	t.Run("Young", func(t *testing.T) {
+		release := &Release{PublishedAt: now.Add(-2 * time.Hour)}
+
+		assert.Equal(t, 70*time.Hour, releaseAgeRemaining(binary, release, now))
+	})
+
+	t.Run("Old", func(t *testing.T) {
+		release := &Release{PublishedAt: now.Add(-100 * time.Hour)}
+
+		assert.LessOrEqual(t, releaseAgeRemaining(binary, release, now), time.Duration(0))
+	})
+
+	t.Run("NoPublicationTime", func(t *testing.T) {
+		assert.Equal(t, 72*time.Hour, releaseAgeRemaining(binary, &Release{}, now))
+	})
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		release := &Release{PublishedAt: now}
+
+		assert.Equal(t, time.Duration(0), releaseAgeRemaining(&Binary{}, release, now))
+	})
+}
+
+// Test that releases without a publication time are held back by a minimum release age, unless it is disabled.
+func TestUpdateMinReleaseAge_Undated(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/min-release-age")
+
+	gCtx, err := NewGrabContext(configDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gCtx.Binaries[slices.IndexFunc(gCtx.Binaries, func(b *Binary) bool { return b.Name == "baz" })].MinReleaseAge = 0
+
+	updater := Updater{
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{Name: "2.0.0"},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	err = updater.Update(t.Context(), gCtx, "", out)
+
+	assert.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "bar: 1.0.0 -> 2.0.0 held back, release date unknown\n")
+	assert.Contains(t, output, "baz: 1.2.3 -> 2.0.0\n")
+
+	asserth.FileContents(t, path.Join(configDir, "config.yml"),
+		"packages:\n  bar: 1.0.0\n  baz: 2.0.0\nsettings:\n  minReleaseAge: 72h\n")
+}
+
+// Test that checking for updates reports statuses without modifying the config.
+func TestCheck(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/multiple")
+
+	gCtx, err := NewGrabContext(configDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updater := Updater{
+		Sources: &Sources{
+			GitHubClient: &githubh.MockGitHubClient{
+				Release: &github.Release{
+					Name: "1.2.3",
+					URL:  "https://fakegithub.com/release-information",
+				},
+			},
+		},
+	}
+
+	statuses, err := updater.Check(t.Context(), gCtx, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []PackageStatus{
+		{
+			Name:           "bar",
+			CurrentVersion: "1.0.0",
+			LatestVersion:  "1.2.3",
+			URL:            "https://fakegithub.com/release-information",
+			Status:         StatusOutdated,
+		},
+		{
+			Name:           "baz",
+			CurrentVersion: "1.2.3",
+			LatestVersion:  "1.2.3",
+			URL:            "https://fakegithub.com/release-information",
+			Status:         StatusCurrent,
+		},
+	}, statuses)
+	assert.Equal(t, 1, CountOutdated(statuses))
+
+	asserth.FileContents(t, path.Join(configDir, "config.yml"), "packages:\n  bar: 1.0.0\n  baz: 1.2.3\n")
+}
+
+// Test that latest releases are looked up in a batch, falling back to individual lookups for missing releases.
+func TestCheckBatchLookup(t *testing.T) {
+	configDir := osh.CopyDir(t, "testdata/contexts/multiple")
+
+	gCtx, err := NewGrabContext(configDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &githubh.MockBatchGitHubClient{
+		MockGitHubClient: githubh.MockGitHubClient{
+			Release: &github.Release{Name: "1.2.3"},
+		},
+		LatestReleases: map[string]*github.Release{
+			"foo/bar": {Name: "2.0.0"},
+		},
+	}
+
+	updater := Updater{
+		Sources: &Sources{
+			GitHubClient: client,
+		},
+	}
+
+	statuses, err := updater.Check(t.Context(), gCtx, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "2.0.0", statuses[0].LatestVersion)
+	assert.Equal(t, "1.2.3", statuses[1].LatestVersion)
+
+	assert.Len(t, client.GetLatestReleasesCalls, 1)
+	assert.ElementsMatch(t, []github.Repository{{Owner: "foo", Name: "bar"}, {Owner: "foo", Name: "baz"}},
+		client.GetLatestReleasesCalls[0])
+	assert.Equal(t, []githubh.GetLatestReleaseCall{{Org: "foo", Repo: "baz"}}, client.GetLatestReleaseCalls)
+}